@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+type checksumInfo struct {
+	Value uint16 `json:"value"`
+	Valid bool   `json:"valid"`
+}
+
+type romInfo struct {
+	Title            string       `json:"title"`
+	ManufacturerCode string       `json:"manufacturerCode"`
+	Licensee         string       `json:"licensee"`
+	OldLicenseeCode  uint8        `json:"oldLicenseeCode"`
+	NewLicenseeCode  string       `json:"newLicenseeCode"`
+	CartridgeType    string       `json:"cartridgeType"`
+	CartridgeCode    uint8        `json:"cartridgeTypeCode"`
+	CGB              string       `json:"cgb"`
+	SGB              bool         `json:"sgb"`
+	Destination      string       `json:"destination"`
+	ROMSize          uint         `json:"romSize"`
+	ROMBanks         uint         `json:"romBanks"`
+	RAMSize          uint         `json:"ramSize"`
+	RAMBanks         uint         `json:"ramBanks"`
+	FileSize         uint         `json:"fileSize"`
+	SizeMatches      bool         `json:"sizeMatches"`
+	LogoValid        bool         `json:"logoValid"`
+	HeaderChecksum   checksumInfo `json:"headerChecksum"`
+	GlobalChecksum   checksumInfo `json:"globalChecksum"`
+}
+
+func newROMInfo(game *gamepak.GamePak) romInfo {
+	return romInfo{
+		Title:            game.Title(),
+		ManufacturerCode: game.ManufacturerCode(),
+		Licensee:         game.LicenseeCode(),
+		OldLicenseeCode:  game.OldLicenseeCode(),
+		NewLicenseeCode:  game.NewLicenseeCode(),
+		CartridgeType:    game.CartridgeType().String(),
+		CartridgeCode:    uint8(game.CartridgeType()),
+		CGB:              game.CGBFlag().String(),
+		SGB:              game.SGBFlag(),
+		Destination:      game.DestinationCode().String(),
+		ROMSize:          game.RomSize(),
+		ROMBanks:         game.BankCount(),
+		RAMSize:          game.RamSize(),
+		RAMBanks:         game.RamBankCount(),
+		FileSize:         game.Size(),
+		SizeMatches:      game.Size() == game.RomSize(),
+		LogoValid:        game.ValidateNintendoLogo(),
+		HeaderChecksum:   checksumInfo{Value: uint16(game.HeaderChecksum()), Valid: game.ComputeHeaderChecksum()},
+		GlobalChecksum:   checksumInfo{Value: game.GlobalChecksum(), Valid: game.ComputeGlobalChecksum()},
+	}
+}
+
+// info prints the cartridge header of a ROM, as text or JSON.
+func info(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("info", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the header as JSON")
+	var patches patchList
+	fs.Var(&patches, "patch", "apply an IPS, BPS or UPS patch (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("info: expected exactly one ROM path")
+	}
+
+	romData, err := loadROM(fs.Arg(0), patches)
+	if err != nil {
+		return err
+	}
+
+	ri := newROMInfo(gamepak.NewGamePak(romData))
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ri)
+	}
+
+	fmt.Fprintf(w, "Title:           %s\n", ri.Title)
+	fmt.Fprintf(w, "Manufacturer:    %s\n", ri.ManufacturerCode)
+	if ri.OldLicenseeCode == 0x33 {
+		fmt.Fprintf(w, "Licensee:        %s (new code %q)\n", ri.Licensee, ri.NewLicenseeCode)
+	} else {
+		fmt.Fprintf(w, "Licensee:        %s (old code 0x%02X)\n", ri.Licensee, ri.OldLicenseeCode)
+	}
+	fmt.Fprintf(w, "Cartridge type:  %s (0x%02X)\n", ri.CartridgeType, ri.CartridgeCode)
+	fmt.Fprintf(w, "CGB:             %s\n", ri.CGB)
+	fmt.Fprintf(w, "SGB:             %t\n", ri.SGB)
+	fmt.Fprintf(w, "Destination:     %s\n", ri.Destination)
+	fmt.Fprintf(w, "ROM size:        %d KiB (%d banks)\n", ri.ROMSize/1024, ri.ROMBanks)
+	fmt.Fprintf(w, "RAM size:        %d KiB (%d banks)\n", ri.RAMSize/1024, ri.RAMBanks)
+	fmt.Fprintf(w, "File size:       %d KiB (%s)\n", ri.FileSize/1024, matchString(ri.SizeMatches, "matches header", "does not match header"))
+	fmt.Fprintf(w, "Nintendo logo:   %s\n", matchString(ri.LogoValid, "valid", "invalid"))
+	fmt.Fprintf(w, "Header checksum: 0x%02X (%s)\n", ri.HeaderChecksum.Value, matchString(ri.HeaderChecksum.Valid, "valid", "invalid"))
+	fmt.Fprintf(w, "Global checksum: 0x%04X (%s)\n", ri.GlobalChecksum.Value, matchString(ri.GlobalChecksum.Valid, "valid", "invalid"))
+	return nil
+}
+
+func matchString(ok bool, yes, no string) string {
+	if ok {
+		return yes
+	}
+	return no
+}
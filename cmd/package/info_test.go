@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+// writeROM writes a 32 KiB ROM with a valid header to a temporary file.
+// romSize is the header's ROM size code, so anything but 0 mismatches the file size.
+func writeROM(t *testing.T, romSize uint8) string {
+	t.Helper()
+
+	data := make([]byte, 0x8000)
+	copy(data[0x0104:], gamepak.NintendoLogo[:])
+	copy(data[0x0134:], "TESTGAME")
+	data[0x0143] = 0x80 // CGB enhanced
+	data[0x0147] = 0x01 // MBC1
+	data[0x0148] = romSize
+	data[0x014B] = 0x01
+
+	var sum uint8
+	for i := 0x0134; i <= 0x014C; i++ {
+		sum = sum - data[i] - 1
+	}
+	data[0x014D] = sum
+
+	path := filepath.Join(t.TempDir(), "test.gb")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestInfo(t *testing.T) {
+	var buf bytes.Buffer
+	if err := info([]string{writeROM(t, 0)}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"Title:           TESTGAME\n",
+		"Cartridge type:  MBC1 (0x01)\n",
+		"ROM size:        32 KiB (2 banks)\n",
+		"File size:       32 KiB (matches header)\n",
+		"Nintendo logo:   valid\n",
+		"(valid)\nGlobal checksum",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInfo_SizeMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	if err := info([]string{writeROM(t, 1)}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "File size:       32 KiB (does not match header)\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %q:\n%s", want, buf.String())
+	}
+}
+
+func TestInfo_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := info([]string{"--json", writeROM(t, 1)}, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var ri romInfo
+	if err := json.Unmarshal(buf.Bytes(), &ri); err != nil {
+		t.Fatal(err)
+	}
+	if ri.Title != "TESTGAME" {
+		t.Errorf("title = %q, want %q", ri.Title, "TESTGAME")
+	}
+	if ri.CartridgeCode != 0x01 {
+		t.Errorf("cartridge type = 0x%02X, want 0x01", ri.CartridgeCode)
+	}
+	if ri.ROMSize != 0x10000 || ri.ROMBanks != 4 || ri.FileSize != 0x8000 {
+		t.Errorf("rom size = %d (%d banks), file size = %d, want 65536 (4 banks) and 32768", ri.ROMSize, ri.ROMBanks, ri.FileSize)
+	}
+	if ri.SizeMatches {
+		t.Error("sizeMatches = true, want false")
+	}
+	if !ri.LogoValid || !ri.HeaderChecksum.Valid {
+		t.Errorf("logo valid = %t, header checksum valid = %t, want both true", ri.LogoValid, ri.HeaderChecksum.Valid)
+	}
+}
+
+func TestInfo_ShortROM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "short.gb")
+	if err := os.WriteFile(path, make([]byte, 0x100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := info([]string{path}, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for a ROM without a header")
+	}
+}
@@ -1,28 +1,201 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/colecrouter/gameboy-go/pkg/system"
+	"github.com/colecrouter/gameboy-go/private/cheats"
+	bootroms "github.com/colecrouter/gameboy-go/private/memory/roms"
 	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+	"github.com/colecrouter/gameboy-go/private/reader/patch"
+	"github.com/colecrouter/gameboy-go/private/reader/rom"
+	"github.com/colecrouter/gameboy-go/private/reader/save"
+	hw "github.com/colecrouter/gameboy-go/private/system"
 	"github.com/colecrouter/gameboy-go/private/ui/terminal"
 )
 
+const usage = `Usage:
+  gameboy [run] [--model name] [--boot-rom file] [--patch file]...
+        [--profile file] [--color-correction=false] <rom>
+                                                 Run a ROM in the terminal
+  gameboy info [--json] [--patch file]... <rom>  Print the cartridge header of a ROM
+
+Patches named after the ROM (game.ips, game.ups, game.bps) are applied
+automatically unless --patch is given. Multiple patches apply in order.
+Cheats are loaded from a .cht file named after the ROM, and toggled from
+the cheat menu (c).
+
+--profile records every memory access from launch and writes the counts to
+a CSV file on exit. The heatmap menu (p) records and exports on demand.
+
+Models are DMG0, DMG, MGB, SGB and CGB. Games with CGB support run on a CGB
+unless a model is given. Only the DMG boot ROM is built in; other models
+skip the boot ROM unless one is given with --boot-rom. CGB colors are
+adjusted to match the CGB's screen; --color-correction=false shows them
+as is.
+`
+
 func main() {
-	gb := system.NewGameBoy()
+	args := os.Args[1:]
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch args[0] {
+	case "info":
+		err = info(args[1:], os.Stdout)
+	case "run":
+		err = run(args[1:])
+	default:
+		err = run(args)
+	}
 
-	// romData, err := os.ReadFile("tetris.gb")
-	// romData, err := os.ReadFile("./tests/blargg/cpu_instrs/cpu_instrs.gb")
-	romData, err := os.ReadFile("./tests/blargg/instr_timing/instr_timing.gb")
-	// romData, err := os.ReadFile("./tests/blargg/interrupt_time/interrupt_time.gb")
 	if err != nil {
 		log.Fatalln(err)
 	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(fs.Output(), usage) }
+	var patches patchList
+	fs.Var(&patches, "patch", "apply an IPS, BPS or UPS patch (repeatable)")
+	modelName := fs.String("model", "", "hardware model to emulate")
+	bootROMPath := fs.String("boot-rom", "", "boot ROM to run before the game")
+	profilePath := fs.String("profile", "", "write memory access counts to a CSV file on exit")
+	colorCorrection := fs.Bool("color-correction", true, "show CGB colors as they look on the CGB's screen")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	romPath := fs.Arg(0)
+
+	opts, err := modelOptions(*modelName, *bootROMPath)
+	if err != nil {
+		return err
+	}
+
+	romData, err := loadROM(romPath, patches)
+	if err != nil {
+		return err
+	}
+
+	// Color games run on a CGB unless told otherwise
 	game := gamepak.NewGamePak(romData)
-	gb.CartridgeReader.InsertCartridge(game)
+	if *modelName == "" && *bootROMPath == "" && game.CGBFlag() != gamepak.CGB_NONE {
+		opts = append(opts, system.WithModel(hw.MODEL_CGB))
+	}
+
+	gb := system.NewGameBoy(opts...)
+	gb.SetColorCorrection(*colorCorrection)
+	gb.SetSaveStorage(save.NewFile(romPath))
+	gb.InsertCartridge(game)
+
+	if err := gb.Cheats().LoadFile(cheats.FilePath(romPath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if *profilePath != "" {
+		gb.Profiler().Start()
+	}
 
 	app := terminal.NewApplication(gb)
 
 	app.Run(false)
+
+	if *profilePath != "" {
+		return writeProfile(gb, *profilePath)
+	}
+	return nil
+}
+
+// writeProfile stops the profiler and saves its counts.
+func writeProfile(gb *system.GameBoy, path string) error {
+	gb.Profiler().Stop()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gb.Profiler().WriteCSV(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// patchList collects repeated --patch flags.
+type patchList []string
+
+func (p *patchList) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *patchList) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+// loadROM reads and unpacks a ROM, then applies the given patches in order.
+// Without explicit patches, any found beside the ROM are used.
+func loadROM(romPath string, patches []string) ([]byte, error) {
+	romData, err := rom.Load(romPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(patches) == 0 {
+		patches = patch.Find(romPath)
+	}
+	romData, err = patch.ApplyFiles(romData, patches)
+	if err != nil {
+		return nil, err
+	}
+	if len(romData) < gamepak.HEADER_END {
+		return nil, fmt.Errorf("%s is too small to have a cartridge header", romPath)
+	}
+	return romData, nil
+}
+
+// modelOptions turns the --model and --boot-rom flags into GameBoy options.
+// A boot ROM without a model has its model worked out from its hash.
+func modelOptions(modelName, bootROMPath string) ([]system.Option, error) {
+	var opts []system.Option
+
+	var model hw.Model
+	if modelName != "" {
+		var ok bool
+		if model, ok = hw.ParseModel(strings.ToUpper(modelName)); !ok {
+			return nil, fmt.Errorf("unknown model %q", modelName)
+		}
+		opts = append(opts, system.WithModel(model))
+	}
+
+	if bootROMPath != "" {
+		data, err := os.ReadFile(bootROMPath)
+		if err != nil {
+			return nil, err
+		}
+
+		var b *bootroms.BootROM
+		if modelName != "" {
+			b, err = bootroms.New(model, data)
+		} else {
+			b, err = bootroms.Identify(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", bootROMPath, err)
+		}
+		opts = append(opts, system.WithBootROM(b))
+	}
+
+	return opts, nil
 }
@@ -0,0 +1,128 @@
+package system
+
+import (
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+// cpuState is the CPU register state a boot ROM leaves behind.
+type cpuState struct {
+	A, F, B, C, D, E, H, L uint8
+}
+
+// https://gbdev.io/pandocs/Power_Up_Sequence.html#cpu-registers
+var postBootCPU = map[system.Model]cpuState{
+	system.MODEL_DMG0: {A: 0x01, F: 0x00, B: 0xFF, C: 0x13, D: 0x00, E: 0xC1, H: 0x84, L: 0x03},
+	system.MODEL_DMG:  {A: 0x01, F: 0x80, B: 0x00, C: 0x13, D: 0x00, E: 0xD8, H: 0x01, L: 0x4D},
+	system.MODEL_MGB:  {A: 0xFF, F: 0x80, B: 0x00, C: 0x13, D: 0x00, E: 0xD8, H: 0x01, L: 0x4D},
+	system.MODEL_SGB:  {A: 0x01, F: 0x00, B: 0x00, C: 0x14, D: 0x00, E: 0x00, H: 0xC0, L: 0x60},
+	system.MODEL_CGB:  {A: 0x11, F: 0x80, B: 0x00, C: 0x00, D: 0xFF, E: 0x56, H: 0x00, L: 0x0D},
+}
+
+// cgbCompatCPU is what the CGB boot ROM leaves behind when it starts a DMG game.
+// B and HL depend on the game's title, this is what it leaves for most games.
+var cgbCompatCPU = cpuState{A: 0x11, F: 0x80, B: 0x00, C: 0x00, D: 0x00, E: 0x08, H: 0x00, L: 0x7C}
+
+// postBootState sets up the CPU and hardware registers as the model's boot ROM would have left them.
+func (gb *GameBoy) postBootState() {
+	state, ok := postBootCPU[gb.model]
+	if !ok {
+		state = postBootCPU[system.MODEL_DMG]
+	}
+
+	// A CGB only runs in color mode for games that support it
+	if gb.model == system.MODEL_CGB {
+		game := gb.CartridgeReader.Cartridge()
+		gb.IO.CGBMode = game != nil && game.CGBFlag() != gamepak.CGB_NONE
+		if !gb.IO.CGBMode {
+			state = cgbCompatCPU
+		}
+	}
+
+	// The DMG and MGB boot ROMs leave H and C set unless the header checksum is 0
+	if gb.model == system.MODEL_DMG || gb.model == system.MODEL_MGB {
+		if game := gb.CartridgeReader.Cartridge(); game == nil || game.HeaderChecksum() != 0 {
+			state.F |= 0x30
+		}
+	}
+
+	reg := gb.CPU.Registers()
+	reg.PC = 0x0100
+	reg.SP = 0xFFFE
+	reg.A = state.A
+	reg.B = state.B
+	reg.C = state.C
+	reg.D = state.D
+	reg.E = state.E
+	reg.H = state.H
+	reg.L = state.L
+
+	fl := gb.CPU.Flags()
+	fl.Write(state.F)
+
+	// https://gbdev.io/pandocs/Power_Up_Sequence.html#hardware-registers
+	gb.IO.Write(0x00, 0xCF) // Joypad input
+	gb.IO.Write(0x01, 0x00) // Serial transfer
+	gb.IO.Write(0x02, 0x7E) // Serial transfer
+	gb.IO.Write(0x04, 0xAB) // Timer and divider
+	gb.IO.Write(0x05, 0x00) // Timer counter
+	gb.IO.Write(0x06, 0x00) // Timer modulo
+	gb.IO.Write(0x07, 0xF8) // Timer control
+	gb.IO.Write(0x0F, 0xE1) // Interrupts
+
+	// Audio
+	gb.IO.Write(0x10, 0x80)
+	gb.IO.Write(0x11, 0xBF)
+	gb.IO.Write(0x12, 0xF3)
+	gb.IO.Write(0x13, 0xFF)
+	gb.IO.Write(0x14, 0xBF)
+	gb.IO.Write(0x16, 0x3F)
+	gb.IO.Write(0x17, 0x00)
+	gb.IO.Write(0x18, 0xFF)
+	gb.IO.Write(0x19, 0xBF)
+	gb.IO.Write(0x1A, 0x7F)
+	gb.IO.Write(0x1B, 0xFF)
+	gb.IO.Write(0x1C, 0x9F)
+	gb.IO.Write(0x1D, 0xFF)
+	gb.IO.Write(0x1E, 0xBF)
+	gb.IO.Write(0x20, 0xFF)
+	gb.IO.Write(0x21, 0x00)
+	gb.IO.Write(0x22, 0x00)
+	gb.IO.Write(0x23, 0xBF)
+	gb.IO.Write(0x24, 0x77)
+	gb.IO.Write(0x25, 0xF3)
+	if gb.model == system.MODEL_SGB {
+		gb.IO.Write(0x26, 0xF0)
+	} else {
+		gb.IO.Write(0x26, 0xF1)
+	}
+
+	gb.IO.Write(0x40, 0x91) // LCD Control
+	gb.IO.Write(0x41, 0x85) // LCD Status
+	gb.IO.Write(0x42, 0x00) // Scroll Y
+	gb.IO.Write(0x43, 0x00) // Scroll X
+	gb.IO.Write(0x44, 0x00) // LY
+	gb.IO.Write(0x45, 0x00) // LY Compare
+	gb.IO.DMA = 0xFF        // DMA, set directly so no transfer starts
+	gb.IO.Write(0x47, 0xFC) // Palette Data
+	// gb.IO.Write(0x48, 0xFF) // Object Palette Data 1
+	// gb.IO.Write(0x49, 0xFF) // Object Palette Data 2
+	gb.IO.Write(0x4A, 0x00) // Window Y
+	gb.IO.Write(0x4B, 0x00) // Window X
+	// CGB only
+	gb.IO.Speed.Reset()     // KEY1, normal speed
+	gb.IO.VRAMBank1 = false // VBK
+	gb.IO.WRAMBank = 0      // SVBK
+
+	// The boot ROM clears the BG palettes to white for color games
+	if gb.IO.CGBMode {
+		for i := range gb.IO.BGPalettes.RAM {
+			gb.IO.BGPalettes.RAM[i] = 0xFF
+		}
+	}
+
+	gb.IO.Write(0xFF, 0x00) // Interrupt Enable Register
+
+	// Disable boot ROM
+	gb.IO.DisableBootROM = true
+}
@@ -1,15 +1,26 @@
 package system
 
 import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/colecrouter/gameboy-go/private/cheats"
 	"github.com/colecrouter/gameboy-go/private/memory"
 	"github.com/colecrouter/gameboy-go/private/memory/io"
+	bootroms "github.com/colecrouter/gameboy-go/private/memory/roms"
 	"github.com/colecrouter/gameboy-go/private/memory/vram"
 	"github.com/colecrouter/gameboy-go/private/processor/cpu/lr35902"
 	"github.com/colecrouter/gameboy-go/private/processor/ppu"
+	"github.com/colecrouter/gameboy-go/private/profiler"
 	"github.com/colecrouter/gameboy-go/private/reader"
 	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+	"github.com/colecrouter/gameboy-go/private/reader/mbc"
+	"github.com/colecrouter/gameboy-go/private/reader/save"
 	"github.com/colecrouter/gameboy-go/private/system"
 )
 
@@ -22,138 +33,125 @@ const DISPLAY_SPEED = 60
 const FRAME_DURATION = time.Duration((float32(time.Second) * 1.0045) / DISPLAY_SPEED)
 const TARGET_CYCLES_PER_FRAME = CLOCK_SPEED / DISPLAY_SPEED
 
+// How often battery-backed RAM is written back while running
+const SAVE_INTERVAL_FRAMES = 5 * DISPLAY_SPEED
+
 type GameBoy struct {
 	Bus             *memory.Bus
 	IO              *io.Registers
 	CPU             *lr35902.LR35902
 	PPU             *ppu.PPU
 	VRAM            *vram.VRAM
+	WRAM            *memory.WRAM
 	CartridgeReader reader.CartridgeReader
 	IF              *io.Interrupt
 	IE              *io.Interrupt
 
-	done         chan struct{}
+	done         chan struct{} // Stops the components
+	quit         chan struct{} // Asks Start's frame loop to stop between frames
+	running      atomic.Bool   // Set while Start's frame loop runs
+	stopped      chan struct{} // Closed once Start's frame loop has returned
+	haltOnce     sync.Once
 	totalTCycles uint64
 	broadcaster  system.Broadcaster
 	FastMode     bool
+
+	model   system.Model
+	bootROM *bootroms.BootROM
+
+	unblocked atomic.Bool // Lets the CPU access VRAM and OAM while the PPU is using them
+
+	tilt     mbc.Accelerometer
+	cheats   cheats.Engine
+	profiler *profiler.Profiler
+
+	saveMu   sync.Mutex
+	saves    save.Storage
+	lastSave []byte
 }
 
-func NewGameBoy() *GameBoy {
-	gb := &GameBoy{}
+func NewGameBoy(opts ...Option) *GameBoy {
+	gb := &GameBoy{model: system.MODEL_DMG, bootROM: bootroms.DMG}
+	for _, opt := range opts {
+		opt(gb)
+	}
 
 	gb.Bus = &memory.Bus{}
 	gb.VRAM = &vram.VRAM{}
 	gb.IF = &io.Interrupt{}
 	gb.IE = &io.Interrupt{}
 	gb.IO = io.NewRegisters(&gb.broadcaster, gb.Bus, gb.IF)
+	gb.WRAM = memory.NewWRAM(&gb.IO.WRAMBank)
+	gb.IO.CGBMode = gb.model == system.MODEL_CGB // Until the boot ROM has checked the cartridge
+	gb.VRAM.ConnectBankSelect(&gb.IO.VRAMBank1)
 	oamModule := memory.NewOAM(gb.VRAM, &gb.IO.LCDControl.Sprites8x16)
 	gb.CPU = lr35902.NewLR35902(&gb.broadcaster, gb.Bus, gb.IO, gb.IE)
 	gb.CartridgeReader = *reader.NewCartridgeReader(&gb.IO.DisableBootROM)
+	gb.CartridgeReader.SetBootROM(gb.bootROM)
+	gb.CartridgeReader.ConnectAccelerometer(&gb.tilt)
 
 	gb.done = make(chan struct{}) // initialize done channel
+	gb.quit = make(chan struct{})
+	gb.stopped = make(chan struct{})
 
 	// gb.memoryBus.AddDevice(0x0000, 0x3FFF, &memory.Memory{Buffer: make([]byte, 0x4000)}) // ROM Bank 0
 	// gb.memoryBus.AddDevice(0x4000, 0x7FFF, &memory.Memory{Buffer: make([]byte, 0x4000)}) // ROM Bank 1-xx aka mapper
 	gb.Bus.AddDevice(0x0000, 0x7FFF, &gb.CartridgeReader)
-	gb.Bus.AddDevice(0x8000, 0x9FFF, gb.VRAM)                                      // VRAM
-	gb.Bus.AddDevice(0xA000, 0xBFFF, &memory.Memory{Buffer: make([]byte, 0x2000)}) // External RAM
-	gb.Bus.AddDevice(0xC000, 0xCFFF, &memory.Memory{Buffer: make([]byte, 0x1000)}) // WRAM
-	gb.Bus.AddDevice(0xD000, 0xDFFF, &memory.Memory{Buffer: make([]byte, 0x1000)}) // WRAM
-	gb.Bus.AddDevice(0xE000, 0xFDFF, &memory.Memory{Buffer: make([]byte, 0x1E00)}) // ECHO RAM
-	gb.Bus.AddDevice(0xFE00, 0xFE9F, oamModule)                                    // OAM
-	// https://gbdev.io/pandocs/Memory_Map.html#fea0feff-range
-	gb.Bus.AddDevice(0xFEA0, 0xFEFF, &memory.Memory{Buffer: make([]byte, 0x60)}) // Unusable Memory
-	gb.Bus.AddDevice(0xFF00, 0xFF7F, gb.IO)                                      // I/O Registers
-	gb.Bus.AddDevice(0xFF80, 0xFFFE, &memory.Memory{Buffer: make([]byte, 0x7F)}) // High RAM
-	gb.Bus.AddDevice(0xFFFF, 0xFFFF, gb.IE)                                      // Interrupt Enable Register
+	gb.Bus.AddDevice(0x8000, 0x9FFF, memory.NewGuarded(gb.VRAM, gb.vramBlocked))    // VRAM
+	gb.Bus.AddDevice(0xA000, 0xBFFF, gb.CartridgeReader.ExternalRAM())              // External RAM
+	gb.Bus.AddDevice(0xC000, 0xDFFF, gb.WRAM)                                       // WRAM
+	gb.Bus.AddMirror(0xE000, 0xFDFF, 0xC000)                                        // ECHO RAM
+	gb.Bus.AddDevice(0xFE00, 0xFE9F, memory.NewGuarded(oamModule, gb.oamBlocked))   // OAM
+	gb.Bus.AddDevice(0xFEA0, 0xFEFF, memory.NewProhibited(gb.model, gb.oamBlocked)) // Unusable Memory
+	gb.Bus.AddDevice(0xFF00, 0xFF7F, gb.IO)                                         // I/O Registers
+	gb.Bus.AddDevice(0xFF80, 0xFFFE, &memory.Memory{Buffer: make([]byte, 0x7F)})    // High RAM
+	gb.Bus.AddDevice(0xFFFF, 0xFFFF, gb.IE)                                         // Interrupt Enable Register
+
+	gb.IO.ConnectOAM(oamModule)
+	gb.IO.ConnectVRAM(gb.VRAM)
+	gb.IO.ConnectPaletteLock(gb.vramBlocked) // Palette RAM is locked whenever VRAM is
+	gb.Bus.AddMaster(&gb.IO.DMATransfer)
 
 	gb.PPU = ppu.NewPPU(&gb.broadcaster, gb.VRAM, oamModule, gb.IO, gb.IF)
 
+	// Bus hooks report where and when each access happened
+	gb.Bus.SetTracer(gb.CPU.LastPC, gb.TotalCycles)
+	gb.profiler = profiler.New(gb.Bus, gb.romBank)
+
+	// Game Genie codes patch cartridge reads, GameShark codes are rewritten every frame.
+	gb.CartridgeReader.SetROMPatcher(&gb.cheats)
+	gb.PPU.OnVBlank(gb.applyCheats)
+
 	return gb
 }
 
 func (gb *GameBoy) Start(skip bool) {
-	if skip {
-		reg := gb.CPU.Registers()
-
-		// Initialize registers to default DMG ROM values
-		// https://gbdev.io/pandocs/Power_Up_Sequence.html#cpu-registers
-		reg.PC = 0x0100
-
-		reg.B = 0x00
-		reg.A = 0x01
-		reg.C = 0x13
-		reg.D = 0x00
-		reg.E = 0xD8
-		reg.H = 0x01
-		reg.L = 0x4D
-		reg.SP = 0xFFFE
-
-		fl := gb.CPU.Flags()
-		fl.Write(0xB0)
-
-		gb.IO.Write(0x00, 0xCF) // Joypad input
-		gb.IO.Write(0x01, 0x00) // Serial transfer
-		gb.IO.Write(0x02, 0x7E) // Serial transfer
-		gb.IO.Write(0x04, 0xAB) // Timer and divider
-		gb.IO.Write(0x05, 0x00) // Timer counter
-		gb.IO.Write(0x06, 0x00) // Timer modulo
-		gb.IO.Write(0x07, 0xF8) // Timer control
-		gb.IO.Write(0x0F, 0xE1) // Interrupts
-
-		// Audio
-		gb.IO.Write(0x10, 0x80)
-		gb.IO.Write(0x11, 0xBF)
-		gb.IO.Write(0x12, 0xF3)
-		gb.IO.Write(0x13, 0xFF)
-		gb.IO.Write(0x14, 0xBF)
-		gb.IO.Write(0x16, 0x3F)
-		gb.IO.Write(0x17, 0x00)
-		gb.IO.Write(0x18, 0xFF)
-		gb.IO.Write(0x19, 0xBF)
-		gb.IO.Write(0x1A, 0x7F)
-		gb.IO.Write(0x1B, 0xFF)
-		gb.IO.Write(0x1C, 0x9F)
-		gb.IO.Write(0x1D, 0xFF)
-		gb.IO.Write(0x1E, 0xBF)
-		gb.IO.Write(0x20, 0xFF)
-		gb.IO.Write(0x21, 0x00)
-		gb.IO.Write(0x22, 0x00)
-		gb.IO.Write(0x23, 0xBF)
-		gb.IO.Write(0x24, 0x77)
-		gb.IO.Write(0x25, 0xF3)
-		gb.IO.Write(0x26, 0xF1)
-
-		gb.IO.Write(0x40, 0x91) // LCD Control
-		gb.IO.Write(0x41, 0x85) // LCD Status
-		gb.IO.Write(0x42, 0x00) // Scroll Y
-		gb.IO.Write(0x43, 0x00) // Scroll X
-		gb.IO.Write(0x44, 0x00) // LY
-		gb.IO.Write(0x45, 0x00) // LY Compare
-		gb.IO.Write(0x46, 0xFF) // DMA
-		gb.IO.Write(0x47, 0xFC) // Palette Data
-		// gb.IO.Write(0x48, 0xFF) // Object Palette Data 1
-		// gb.IO.Write(0x49, 0xFF) // Object Palette Data 2
-		gb.IO.Write(0x4A, 0x00) // Window Y
-		gb.IO.Write(0x4B, 0x00) // Window X
-		// CGB only
-
-		gb.IO.Write(0xFF, 0x00) // Interrupt Enable Register
-
-		// Disable boot ROM
-		gb.IO.DisableBootROM = true
+	gb.running.Store(true)
+	defer close(gb.stopped)
+
+	// Without a boot ROM there is nothing to run, so start from the state it would have left behind.
+	if skip || gb.bootROM == nil {
+		gb.postBootState()
 	}
 
 	// Start CPU, PPU, and Timer in their own goroutines.
 	go gb.CPU.Run(gb.done)
 	go gb.PPU.Run(gb.done)
 	go gb.IO.Timer.Run(gb.done)
+	go gb.IO.DMATransfer.Run(gb.done)
+	go gb.IO.VRAMDMA.Run(gb.done)
 
-	for {
+	for frame := 1; ; frame++ {
 		frameStart := time.Now()
 
+		if frame%SAVE_INTERVAL_FRAMES == 0 {
+			gb.flushSave()
+		}
+
 		select {
-		case <-gb.done:
+		case <-gb.quit:
+			// Between frames, so the game isn't halfway through writing its save
+			gb.halt()
 			return
 		default:
 			for range TARGET_CYCLES_PER_FRAME {
@@ -174,16 +172,172 @@ func (gb *GameBoy) Start(skip bool) {
 	}
 }
 
+// Stop stops the emulation and writes the save. If it's running, the current frame finishes first,
+// so the save isn't taken while the game is still writing to cartridge RAM.
 func (gb *GameBoy) Stop() {
-	close(gb.done)
+	close(gb.quit)
+	if gb.running.Load() {
+		<-gb.stopped
+		return
+	}
+	gb.halt()
+}
+
+// halt stops the components and writes the save.
+func (gb *GameBoy) halt() {
+	gb.haltOnce.Do(func() {
+		close(gb.done)
+		gb.flushSave()
+	})
+}
+
+// oamBlocked reports whether the PPU has OAM locked, during OAM scan and pixel transfer.
+func (gb *GameBoy) oamBlocked() bool {
+	if gb.unblocked.Load() || !gb.IO.LCDControl.EnableLCD {
+		return false
+	}
+	mode := gb.IO.LCDStatus.PPUMode
+	return mode == io.OAMScan || mode == io.Drawing
+}
+
+// vramBlocked reports whether the PPU has VRAM locked, during pixel transfer.
+func (gb *GameBoy) vramBlocked() bool {
+	if gb.unblocked.Load() || !gb.IO.LCDControl.EnableLCD {
+		return false
+	}
+	return gb.IO.LCDStatus.PPUMode == io.Drawing
+}
+
+// SetAccessBlocking sets whether the CPU is locked out of VRAM, OAM and palette RAM while the PPU is using them, as on hardware.
+// It's on by default. Turning it off lets tools read and write video memory at any time.
+func (gb *GameBoy) SetAccessBlocking(on bool) {
+	gb.unblocked.Store(!on)
+}
+
+// Model returns the hardware revision being emulated.
+func (gb *GameBoy) Model() system.Model {
+	return gb.model
+}
+
+// CGBMode reports whether the Game Boy Color features are enabled. This is only the case on a CGB running a game made for it.
+func (gb *GameBoy) CGBMode() bool {
+	return gb.IO.CGBMode
+}
+
+// SetColorCorrection sets whether CGB colors are adjusted to look as they do on the CGB's LCD. It's off by default, showing colors as is.
+func (gb *GameBoy) SetColorCorrection(on bool) {
+	gb.PPU.SetColorCorrection(on)
+}
+
+// DoubleSpeed reports whether the CPU is running in CGB double speed mode.
+func (gb *GameBoy) DoubleSpeed() bool {
+	return gb.broadcaster.DoubleSpeed()
 }
 
 func (gb *GameBoy) PC() uint16 {
 	return gb.CPU.Registers().PC
 }
 
+// InsertCartridge inserts a cartridge, loading its battery-backed RAM from the save storage if it has any.
+// It can be called while running, in which case the previous cartridge's save is written out first.
 func (gb *GameBoy) InsertCartridge(game *gamepak.GamePak) {
+	gb.saveMu.Lock()
+	defer gb.saveMu.Unlock()
+
+	gb.flushSaveLocked()
 	gb.CartridgeReader.InsertCartridge(game)
+	gb.loadSaveLocked()
+}
+
+// RemoveCartridge writes out the cartridge's save and empties the slot. Reads from the slot then return 0xFF.
+func (gb *GameBoy) RemoveCartridge() {
+	gb.saveMu.Lock()
+	defer gb.saveMu.Unlock()
+
+	gb.flushSaveLocked()
+	gb.CartridgeReader.RemoveCartridge()
+	gb.lastSave = nil
+}
+
+// SetSaveStorage sets where battery-backed RAM is kept. It should be set before the cartridge is inserted.
+// When swapping cartridges, the current cartridge's save is written to the old storage first.
+func (gb *GameBoy) SetSaveStorage(s save.Storage) {
+	gb.saveMu.Lock()
+	defer gb.saveMu.Unlock()
+
+	gb.flushSaveLocked()
+	gb.saves = s
+}
+
+// battery returns the battery-backed mapper of the inserted cartridge, if any.
+func (gb *GameBoy) battery() mbc.Battery {
+	game := gb.CartridgeReader.Cartridge()
+	if game == nil || !game.CartridgeType().HasBattery() {
+		return nil
+	}
+	b, _ := gb.CartridgeReader.Mapper().(mbc.Battery)
+	return b
+}
+
+func (gb *GameBoy) loadSaveLocked() {
+	gb.lastSave = nil
+
+	battery := gb.battery()
+	if battery == nil || gb.saves == nil {
+		return
+	}
+
+	data, err := gb.saves.Load()
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("loading save: %v", err)
+		}
+		gb.lastSave = battery.SaveData()
+		return
+	}
+
+	battery.LoadSaveData(data)
+	gb.lastSave = battery.SaveData()
+}
+
+// flushSave writes battery-backed RAM to the save storage if it changed since the last write.
+func (gb *GameBoy) flushSave() {
+	gb.saveMu.Lock()
+	defer gb.saveMu.Unlock()
+
+	gb.flushSaveLocked()
+}
+
+func (gb *GameBoy) flushSaveLocked() {
+	battery := gb.battery()
+	if battery == nil || gb.saves == nil {
+		return
+	}
+
+	data := battery.SaveData()
+	if bytes.Equal(data, gb.lastSave) {
+		return
+	}
+	if err := gb.saves.Store(data); err != nil {
+		log.Printf("writing save: %v", err)
+		return
+	}
+	gb.lastSave = data
+}
+
+// OnRumble registers a function that is called whenever the cartridge's rumble motor turns on or off.
+func (gb *GameBoy) OnRumble(f func(on bool)) {
+	gb.CartridgeReader.OnRumble(f)
+}
+
+// ConnectInfrared connects the infrared port of cartridges that have one, such as HuC1 and HuC3, to a peer.
+func (gb *GameBoy) ConnectInfrared(p mbc.InfraredPeer) {
+	gb.CartridgeReader.ConnectInfrared(p)
+}
+
+// ConnectCamera sets where the Game Boy Camera's sensor gets its frames from.
+func (gb *GameBoy) ConnectCamera(src mbc.ImageSource) {
+	gb.CartridgeReader.ConnectCamera(src)
 }
 
 func (gb *GameBoy) ConnectSerialDevice(d io.SerialDevice) {
@@ -194,6 +348,43 @@ func (gb *GameBoy) Controller() *io.JoyPad {
 	return &gb.IO.JoypadState
 }
 
+// Tilt returns the accelerometer read by cartridges with a motion sensor, such as MBC7.
+func (gb *GameBoy) Tilt() *mbc.Accelerometer {
+	return &gb.tilt
+}
+
+// Cheats returns the Game Genie and GameShark codes applied to the running game.
+func (gb *GameBoy) Cheats() *cheats.Engine {
+	return &gb.cheats
+}
+
+// applyCheats writes the GameShark codes. They aren't CPU accesses, so they skip hooks and bus conflicts.
+func (gb *GameBoy) applyCheats() {
+	gb.cheats.ApplyRAM(gb.Bus.Poke, gb.pokeWRAM)
+}
+
+// pokeWRAM writes a cheat to a specific work RAM bank. Only the CGB has banks to pick from.
+func (gb *GameBoy) pokeWRAM(bank int, addr uint16, val uint8) {
+	if !gb.IO.CGBMode {
+		gb.Bus.Poke(addr, val)
+		return
+	}
+	gb.WRAM.WriteBank(bank, addr-0xD000, val)
+}
+
+// Profiler returns the memory access profiler. It doesn't count anything until started.
+func (gb *GameBoy) Profiler() *profiler.Profiler {
+	return gb.profiler
+}
+
+// romBank returns the ROM bank mapped at a cartridge ROM address.
+func (gb *GameBoy) romBank(addr uint16) uint {
+	if b, ok := gb.CartridgeReader.Mapper().(mbc.Banked); ok {
+		return b.ROMBank(addr)
+	}
+	return uint(addr / 0x4000)
+}
+
 func (gb *GameBoy) TotalCycles() uint64 {
 	return gb.totalTCycles
 }
@@ -6,7 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/colecrouter/gameboy-go/private/cheats"
+	"github.com/colecrouter/gameboy-go/private/memory"
+	"github.com/colecrouter/gameboy-go/private/memory/io"
 	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+	"github.com/colecrouter/gameboy-go/private/system"
 )
 
 // BenchmarkGameBoy_CycleAccurateOneSecond benchmarks the GameBoy's performance by running it for 1 second
@@ -58,3 +62,46 @@ func TestGameBoy_BlarggCPUInstrs(t *testing.T) {
 func TestGameBoy_BlarggInstrTiming(t *testing.T) {
 	RunBlarggTestRom(t, "../../tests/blargg/instr_timing/instr_timing.gb")
 }
+
+func TestCheatsWRAMBank(t *testing.T) {
+	gb := NewGameBoy(WithModel(system.MODEL_CGB))
+	gb.Bus.AddHook(memory.Hook{Types: memory.ACCESS_WRITE, Start: 0x0000, End: 0xFFFF, Func: func(a memory.Access) {
+		t.Errorf("cheat write to %04X fired a hook", a.Addr)
+	}})
+
+	cheat, err := cheats.NewCheat("Shark", "0163B0C1", "9342B0D1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gb.Cheats().Add(cheat)
+	gb.IO.WRAMBank = 2
+	gb.applyCheats()
+
+	if got := gb.Bus.Peek(0xC1B0); got != 0x63 {
+		t.Errorf("C1B0 = %02X, want 63", got)
+	}
+	if got := gb.WRAM.ReadBank(3, 0x01B0); got != 0x42 {
+		t.Errorf("bank 3 D1B0 = %02X, want 42", got)
+	}
+	if got := gb.Bus.Peek(0xD1B0); got != 0x00 {
+		t.Errorf("selected bank D1B0 = %02X, want 00", got)
+	}
+}
+
+func TestAccessBlockingPalettes(t *testing.T) {
+	gb := NewGameBoy(WithModel(system.MODEL_CGB))
+	gb.IO.LCDControl.EnableLCD = true
+	gb.IO.LCDStatus.PPUMode = io.Drawing
+
+	gb.Bus.Write(0xFF68, 0x00) // BCPS, index 0 without auto-increment
+	gb.Bus.Write(0xFF69, 0x12)
+	if got := gb.Bus.Read(0xFF69); got != 0xFF {
+		t.Errorf("locked palette read %02X, want FF", got)
+	}
+
+	gb.SetAccessBlocking(false)
+	gb.Bus.Write(0xFF69, 0x12)
+	if got := gb.Bus.Read(0xFF69); got != 0x12 {
+		t.Errorf("unlocked palette read %02X, want 12", got)
+	}
+}
@@ -0,0 +1,30 @@
+package system
+
+import (
+	bootroms "github.com/colecrouter/gameboy-go/private/memory/roms"
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+// Option configures a GameBoy when it is created.
+type Option func(*GameBoy)
+
+// WithModel sets the hardware revision. Only the DMG boot ROM is built in,
+// so other models start without one unless WithBootROM is also given.
+func WithModel(m system.Model) Option {
+	return func(gb *GameBoy) {
+		gb.model = m
+		if gb.bootROM == bootroms.DMG && m != system.MODEL_DMG {
+			gb.bootROM = nil
+		}
+	}
+}
+
+// WithBootROM sets the boot ROM, and the model to match it. Nil starts without a boot ROM.
+func WithBootROM(b *bootroms.BootROM) Option {
+	return func(gb *GameBoy) {
+		gb.bootROM = b
+		if b != nil {
+			gb.model = b.Model
+		}
+	}
+}
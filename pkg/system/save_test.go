@@ -0,0 +1,103 @@
+package system
+
+import (
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+// memorySave keeps a save in memory.
+type memorySave struct {
+	data []byte
+}
+
+func (m *memorySave) Load() ([]byte, error) {
+	if m.data == nil {
+		return nil, fs.ErrNotExist
+	}
+	return m.data, nil
+}
+
+func (m *memorySave) Store(data []byte) error {
+	m.data = append([]byte(nil), data...)
+	return nil
+}
+
+func TestCameraSave(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	rom[0x0147] = uint8(gamepak.POCKET_CAMERA)
+	rom[0x0149] = 0x04 // 128 KiB
+
+	storage := &memorySave{}
+
+	gb := NewGameBoy()
+	gb.SetSaveStorage(storage)
+	gb.InsertCartridge(gamepak.NewGamePak(rom))
+
+	// Enable RAM writes, and put something in the first and last photo banks
+	gb.Bus.Write(0x0000, 0x0A)
+	gb.Bus.Write(0x4000, 0x00)
+	gb.Bus.Write(0xA000, 0x42)
+	gb.Bus.Write(0x4000, 0x0F)
+	gb.Bus.Write(0xBFFF, 0x99)
+	gb.RemoveCartridge()
+
+	if storage.data == nil {
+		t.Fatal("camera RAM wasn't saved")
+	}
+
+	gb = NewGameBoy()
+	gb.SetSaveStorage(storage)
+	gb.InsertCartridge(gamepak.NewGamePak(rom))
+
+	gb.Bus.Write(0x4000, 0x00)
+	if got := gb.Bus.Read(0xA000); got != 0x42 {
+		t.Errorf("bank 0 after reload = %02X, want 42", got)
+	}
+	gb.Bus.Write(0x4000, 0x0F)
+	if got := gb.Bus.Read(0xBFFF); got != 0x99 {
+		t.Errorf("bank 15 after reload = %02X, want 99", got)
+	}
+}
+
+func TestStopWaitsForFrame(t *testing.T) {
+	rom := make([]byte, 0x8000)
+	rom[0x0100] = 0x76 // HALT
+	rom[0x0147] = uint8(gamepak.MBC1_RAM_BATTERY)
+	rom[0x0149] = 0x02 // 8 KiB
+
+	storage := &memorySave{}
+	gb := NewGameBoy()
+	gb.FastMode = true
+	gb.SetSaveStorage(storage)
+	gb.InsertCartridge(gamepak.NewGamePak(rom))
+	gb.Bus.Write(0x0000, 0x0A)
+	gb.Bus.Write(0xA000, 0x42)
+
+	go gb.Start(true)
+	time.Sleep(50 * time.Millisecond)
+	gb.Stop()
+
+	// Stop returns once the frame loop is done, so nothing runs afterwards
+	if cycles := gb.totalTCycles; cycles == 0 || cycles%TARGET_CYCLES_PER_FRAME != 0 {
+		t.Errorf("stopped after %d cycles, want a whole number of frames", cycles)
+	}
+	if storage.data == nil || storage.data[0] != 0x42 {
+		t.Error("RAM wasn't saved on stop")
+	}
+}
+
+func TestStopWithoutStart(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		NewGameBoy().Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop hung when the Game Boy wasn't running")
+	}
+}
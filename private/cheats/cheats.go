@@ -0,0 +1,149 @@
+package cheats
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Cheat is a named group of codes that are toggled together.
+type Cheat struct {
+	Name    string
+	Codes   []string
+	Enabled bool
+
+	genies []GameGenie
+	sharks []GameShark
+}
+
+// NewCheat parses one or more Game Genie or GameShark codes into a cheat.
+// Game Genie codes contain dashes, GameShark codes are 8 plain hex digits.
+func NewCheat(name string, codes ...string) (*Cheat, error) {
+	c := &Cheat{Name: name, Codes: codes, Enabled: true}
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if strings.Contains(code, "-") {
+			g, err := ParseGameGenie(code)
+			if err != nil {
+				return nil, err
+			}
+			c.genies = append(c.genies, g)
+		} else {
+			s, err := ParseGameShark(code)
+			if err != nil {
+				return nil, err
+			}
+			c.sharks = append(c.sharks, s)
+		}
+	}
+	if len(c.genies) == 0 && len(c.sharks) == 0 {
+		return nil, fmt.Errorf("cheat %q has no codes", name)
+	}
+	return c, nil
+}
+
+// Engine applies the enabled cheats.
+// Game Genie codes patch cartridge reads, GameShark codes are written to RAM on every VBlank.
+type Engine struct {
+	mu     sync.Mutex
+	cheats []*Cheat
+
+	// Snapshots of the enabled codes, rebuilt on every change so the hot paths don't lock
+	genies atomic.Pointer[map[uint16][]GameGenie]
+	sharks atomic.Pointer[[]GameShark]
+}
+
+// Add adds a cheat to the end of the list.
+func (e *Engine) Add(c *Cheat) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cheats = append(e.cheats, c)
+	e.rebuild()
+}
+
+// Clear removes all cheats.
+func (e *Engine) Clear() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cheats = nil
+	e.rebuild()
+}
+
+// Toggle flips whether the i-th cheat is enabled. It returns false if there is no such cheat.
+func (e *Engine) Toggle(i int) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if i < 0 || i >= len(e.cheats) {
+		return false
+	}
+	e.cheats[i].Enabled = !e.cheats[i].Enabled
+	e.rebuild()
+	return true
+}
+
+// Cheats returns a copy of the cheat list.
+func (e *Engine) Cheats() []Cheat {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	list := make([]Cheat, len(e.cheats))
+	for i, c := range e.cheats {
+		list[i] = *c
+	}
+	return list
+}
+
+func (e *Engine) rebuild() {
+	genies := map[uint16][]GameGenie{}
+	var sharks []GameShark
+	for _, c := range e.cheats {
+		if !c.Enabled {
+			continue
+		}
+		for _, g := range c.genies {
+			genies[g.Address] = append(genies[g.Address], g)
+		}
+		sharks = append(sharks, c.sharks...)
+	}
+
+	if len(genies) == 0 {
+		e.genies.Store(nil)
+	} else {
+		e.genies.Store(&genies)
+	}
+	if len(sharks) == 0 {
+		e.sharks.Store(nil)
+	} else {
+		e.sharks.Store(&sharks)
+	}
+}
+
+// PatchROM returns the value a cartridge read of addr should see, given the byte the ROM returned.
+func (e *Engine) PatchROM(addr uint16, val uint8) uint8 {
+	genies := e.genies.Load()
+	if genies == nil {
+		return val
+	}
+	for _, g := range (*genies)[addr] {
+		if !g.HasCompare || g.Compare == val {
+			return g.Value
+		}
+	}
+	return val
+}
+
+// ApplyRAM performs the writes of all enabled GameShark codes.
+// Codes that pick a work RAM bank go to writeBank, the rest to write.
+func (e *Engine) ApplyRAM(write func(addr uint16, val uint8), writeBank func(bank int, addr uint16, val uint8)) {
+	sharks := e.sharks.Load()
+	if sharks == nil {
+		return
+	}
+	for _, s := range *sharks {
+		if bank, ok := s.WRAMBank(); ok {
+			writeBank(bank, s.Address, s.Value)
+			continue
+		}
+		write(s.Address, s.Value)
+	}
+}
@@ -0,0 +1,161 @@
+package cheats
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGameGenie(t *testing.T) {
+	tests := []struct {
+		code string
+		want GameGenie
+	}{
+		{"00A-17B", GameGenie{Address: 0x4A17, Value: 0x00}},
+		{"3E1-5AF", GameGenie{Address: 0x015A, Value: 0x3E}},
+		// GI = 0xC9: rotate right by 2 = 0x72, XOR 0xBA = 0xC8
+		{"00A-17B-C49", GameGenie{Address: 0x4A17, Value: 0x00, Compare: 0xC8, HasCompare: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := ParseGameGenie(tt.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "00A-17", "00A-17B-C4", "0GA-17B", "00A-170"} {
+		if _, err := ParseGameGenie(bad); err == nil {
+			t.Errorf("ParseGameGenie(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseGameShark(t *testing.T) {
+	got, err := ParseGameShark("0163B0C1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := GameShark{Type: 0x01, Value: 0x63, Address: 0xC1B0}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"0163B0C", "0163B0G1", "01630040"} {
+		if _, err := ParseGameShark(bad); err == nil {
+			t.Errorf("ParseGameShark(%q) should fail", bad)
+		}
+	}
+}
+
+func TestGameSharkWRAMBank(t *testing.T) {
+	tests := []struct {
+		code string
+		bank int
+		ok   bool
+	}{
+		{"0163B0C1", 0, false}, // Normal write
+		{"9363B0D1", 3, true},
+		{"9063B0D1", 1, true},  // Bank 0 means bank 1
+		{"9363B0C1", 0, false}, // Bank 0 is fixed
+		{"9863B0D1", 0, false}, // Not a bank type
+	}
+	for _, tt := range tests {
+		s, err := ParseGameShark(tt.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bank, ok := s.WRAMBank(); bank != tt.bank || ok != tt.ok {
+			t.Errorf("%s: got bank %d, %t, want %d, %t", tt.code, bank, ok, tt.bank, tt.ok)
+		}
+	}
+}
+
+func TestEngine(t *testing.T) {
+	var e Engine
+
+	if got := e.PatchROM(0x4A17, 0x12); got != 0x12 {
+		t.Errorf("empty engine patched read to %02X", got)
+	}
+
+	genie, _ := NewCheat("Genie", "00A-17B-C49")
+	shark, _ := NewCheat("Shark", "0163B0C1")
+	e.Add(genie)
+	e.Add(shark)
+
+	t.Run("Compare matches", func(t *testing.T) {
+		if got := e.PatchROM(0x4A17, 0xC8); got != 0x00 {
+			t.Errorf("got %02X, want 00", got)
+		}
+	})
+	t.Run("Compare differs", func(t *testing.T) {
+		if got := e.PatchROM(0x4A17, 0x12); got != 0x12 {
+			t.Errorf("got %02X, want 12", got)
+		}
+	})
+	t.Run("Other address", func(t *testing.T) {
+		if got := e.PatchROM(0x4A18, 0xC8); got != 0xC8 {
+			t.Errorf("got %02X, want C8", got)
+		}
+	})
+
+	t.Run("RAM writes", func(t *testing.T) {
+		writes := map[uint16]uint8{}
+		e.ApplyRAM(func(addr uint16, val uint8) { writes[addr] = val }, func(bank int, addr uint16, val uint8) {
+			t.Errorf("unbanked shark wrote bank %d", bank)
+		})
+		if len(writes) != 1 || writes[0xC1B0] != 0x63 {
+			t.Errorf("got writes %v, want C1B0=63", writes)
+		}
+	})
+
+	t.Run("Toggle", func(t *testing.T) {
+		if !e.Toggle(0) || !e.Toggle(1) {
+			t.Fatal("toggle failed")
+		}
+		if got := e.PatchROM(0x4A17, 0xC8); got != 0xC8 {
+			t.Errorf("disabled genie still patched read to %02X", got)
+		}
+		e.ApplyRAM(func(addr uint16, val uint8) { t.Errorf("disabled shark wrote %04X", addr) }, nil)
+		if e.Toggle(2) {
+			t.Error("toggled a cheat that doesn't exist")
+		}
+		if e.Cheats()[0].Enabled {
+			t.Error("cheat list doesn't reflect toggle")
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	file := `
+# Comment
+0163B0C1 Infinite health
+!00A-17B+3E1-5AF  Two codes
+01FFA0C1
+`
+	list, err := Parse(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("got %d cheats, want 3", len(list))
+	}
+
+	if list[0].Name != "Infinite health" || !list[0].Enabled {
+		t.Errorf("got %+v", list[0])
+	}
+	if list[1].Name != "Two codes" || list[1].Enabled || len(list[1].genies) != 2 {
+		t.Errorf("got %+v", list[1])
+	}
+	if list[2].Name != "01FFA0C1" {
+		t.Errorf("unnamed cheat got name %q", list[2].Name)
+	}
+
+	if _, err := Parse(strings.NewReader("not-a-code")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("got %v, want an error on line 1", err)
+	}
+}
@@ -0,0 +1,90 @@
+package cheats
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GameGenie is a ROM patch. Reads of Address return Value instead,
+// but only when the ROM byte matches Compare, if the code has one.
+// https://gbdev.io/pandocs/Shark_Cheats.html
+type GameGenie struct {
+	Address    uint16
+	Value      uint8
+	Compare    uint8
+	HasCompare bool
+}
+
+// ParseGameGenie decodes a code in the ABC-DEF or ABC-DEF-GHI form.
+//
+//	AB   new value
+//	FCDE address, XORed with 0xF000
+//	GI   compare value, XORed with 0xBA and rotated left by 2. H is unused.
+func ParseGameGenie(code string) (GameGenie, error) {
+	digits := strings.ReplaceAll(code, "-", "")
+	if len(digits) != 6 && len(digits) != 9 {
+		return GameGenie{}, fmt.Errorf("game genie code %q: want 6 or 9 digits", code)
+	}
+	n := make([]uint8, len(digits))
+	for i, c := range digits {
+		v, err := strconv.ParseUint(string(c), 16, 8)
+		if err != nil {
+			return GameGenie{}, fmt.Errorf("game genie code %q: %w", code, err)
+		}
+		n[i] = uint8(v)
+	}
+
+	g := GameGenie{
+		Value:   n[0]<<4 | n[1],
+		Address: (uint16(n[5])<<12 | uint16(n[2])<<8 | uint16(n[3])<<4 | uint16(n[4])) ^ 0xF000,
+	}
+	if g.Address >= 0x8000 {
+		return GameGenie{}, fmt.Errorf("game genie code %q: address %04X is outside ROM", code, g.Address)
+	}
+	if len(n) == 9 {
+		c := n[6]<<4 | n[8]
+		c = c>>2 | c<<6
+		g.Compare = c ^ 0xBA
+		g.HasCompare = true
+	}
+	return g, nil
+}
+
+// GameShark is a RAM write, reapplied every frame.
+// The code is 8 hex digits, TTVVLLHH: type, value and little-endian address.
+// Types 0x90-0x97 write to that bank of CGB work RAM at 0xD000-0xDFFF, instead of the one SVBK selects.
+type GameShark struct {
+	Type    uint8
+	Address uint16
+	Value   uint8
+}
+
+func ParseGameShark(code string) (GameShark, error) {
+	if len(code) != 8 {
+		return GameShark{}, fmt.Errorf("gameshark code %q: want 8 digits", code)
+	}
+	v, err := strconv.ParseUint(code, 16, 32)
+	if err != nil {
+		return GameShark{}, fmt.Errorf("gameshark code %q: %w", code, err)
+	}
+
+	s := GameShark{
+		Type:    uint8(v >> 24),
+		Value:   uint8(v >> 16),
+		Address: uint16(v>>8)&0xFF | uint16(v)<<8,
+	}
+	if s.Address < 0xA000 {
+		return GameShark{}, fmt.Errorf("gameshark code %q: address %04X is not RAM", code, s.Address)
+	}
+	return s, nil
+}
+
+// WRAMBank returns the work RAM bank the code writes to, if it picks one.
+func (s GameShark) WRAMBank() (int, bool) {
+	if s.Type&0xF8 != 0x90 || s.Address < 0xD000 || s.Address > 0xDFFF {
+		return 0, false
+	}
+	// Like SVBK, bank 0 means bank 1
+	return max(int(s.Type&0x07), 1), true
+}
@@ -0,0 +1,72 @@
+package cheats
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FilePath returns the cheat file next to the given ROM, e.g. game.cht for game.gb.
+func FilePath(romPath string) string {
+	return strings.TrimSuffix(romPath, filepath.Ext(romPath)) + ".cht"
+}
+
+// LoadFile adds the cheats from a cheat file. See Parse for the format.
+func (e *Engine) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	list, err := Parse(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, c := range list {
+		e.Add(c)
+	}
+	return nil
+}
+
+// Parse reads a cheat file. Each line holds codes joined by '+', then an optional name:
+//
+//	# Comments start with a hash
+//	01FF1AC1 Infinite health
+//	!00A-17B-C49+01A-18B-C49 Moon jump
+//
+// A leading '!' loads the cheat disabled.
+func Parse(r io.Reader) ([]*Cheat, error) {
+	var list []*Cheat
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		enabled := true
+		if strings.HasPrefix(text, "!") {
+			enabled = false
+			text = strings.TrimSpace(text[1:])
+		}
+
+		codes, name, _ := strings.Cut(text, " ")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = codes
+		}
+
+		c, err := NewCheat(name, strings.Split(codes, "+")...)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		c.Enabled = enabled
+		list = append(list, c)
+	}
+	return list, scanner.Err()
+}
@@ -0,0 +1,31 @@
+package cgb
+
+import "image/color"
+
+// RGBA converts an RGB555 color to 8 bits per channel, as is. Colors come out much brighter and more saturated than on a CGB screen.
+func RGBA(c uint16) color.RGBA {
+	r, g, b := channels(c)
+	return color.RGBA{scale(r), scale(g), scale(b), 255}
+}
+
+// Corrected converts an RGB555 color to how it looks on the CGB's LCD, which mixes the channels and washes colors out.
+// The curve is the one used by Gambatte.
+func Corrected(c uint16) color.RGBA {
+	r, g, b := channels(c)
+	return color.RGBA{
+		uint8((r*13 + g*2 + b) >> 1),
+		uint8((g*3 + b) << 1),
+		uint8((r*3 + g*2 + b*11) >> 1),
+		255,
+	}
+}
+
+// channels splits an RGB555 color into its 5-bit red, green and blue.
+func channels(c uint16) (r, g, b uint16) {
+	return c & 0x1F, (c >> 5) & 0x1F, (c >> 10) & 0x1F
+}
+
+// scale stretches a 5-bit channel to 8 bits, so 31 becomes 255.
+func scale(v uint16) uint8 {
+	return uint8(v<<3 | v>>2)
+}
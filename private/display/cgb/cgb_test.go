@@ -0,0 +1,45 @@
+package cgb
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestRGBA(t *testing.T) {
+	tests := []struct {
+		in   uint16
+		want color.RGBA
+	}{
+		{0x0000, color.RGBA{0, 0, 0, 255}},
+		{0x7FFF, color.RGBA{255, 255, 255, 255}},
+		{0x001F, color.RGBA{255, 0, 0, 255}},
+		{0x03E0, color.RGBA{0, 255, 0, 255}},
+		{0x7C00, color.RGBA{0, 0, 255, 255}},
+		{0x0010, color.RGBA{132, 0, 0, 255}},
+	}
+	for _, tt := range tests {
+		if got := RGBA(tt.in); got != tt.want {
+			t.Errorf("RGBA(%04X) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCorrected(t *testing.T) {
+	// Black and white stay neutral
+	if got := Corrected(0x0000); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("black = %v", got)
+	}
+	white := Corrected(0x7FFF)
+	if white.R != white.G || white.G != white.B {
+		t.Errorf("white isn't gray: %v", white)
+	}
+
+	// Pure colors bleed into the other channels
+	red := Corrected(0x001F)
+	if red.G != 0 || red.B == 0 || red.R <= red.B {
+		t.Errorf("red = %v", red)
+	}
+	if red.R >= RGBA(0x001F).R {
+		t.Errorf("corrected red %v isn't darker than %v", red, RGBA(0x001F))
+	}
+}
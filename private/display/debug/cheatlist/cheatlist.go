@@ -0,0 +1,63 @@
+package cheatlist
+
+import (
+	"fmt"
+
+	"github.com/colecrouter/gameboy-go/private/cheats"
+	"github.com/colecrouter/gameboy-go/private/display"
+	"github.com/colecrouter/gameboy-go/private/ui/terminal/utils"
+)
+
+// The number keys 1-9 select cheats, so only that many can be toggled.
+const MAX_CHEATS = 9
+
+type CheatMenu struct {
+	config display.Config
+	engine *cheats.Engine
+}
+
+func NewCheatMenu(e *cheats.Engine) *CheatMenu {
+	return &CheatMenu{engine: e, config: display.Config{Width: 40 * utils.CHAR_WIDTH, Title: "Cheats", Height: 11 * utils.CHAR_HEIGHT}}
+}
+
+func (c *CheatMenu) Clock() {
+}
+
+// Text lists the cheats with their number key and whether they are enabled.
+func (c *CheatMenu) Text() []string {
+	list := c.engine.Cheats()
+	if len(list) == 0 {
+		return []string{"No cheats loaded"}
+	}
+
+	var text []string
+	for i, cheat := range list {
+		if i == MAX_CHEATS {
+			text = append(text, fmt.Sprintf("...and %d more", len(list)-MAX_CHEATS))
+			break
+		}
+		mark := " "
+		if cheat.Enabled {
+			mark = "x"
+		}
+		name := cheat.Name
+		if len(name) > 32 {
+			name = name[:32]
+		}
+		text = append(text, fmt.Sprintf("%d [%s] %s", i+1, mark, name))
+	}
+	text = append(text, "Press 1-9 to toggle")
+	return text
+}
+
+// HandleKey flips the cheat bound to a number key, returning false if the key isn't bound.
+func (c *CheatMenu) HandleKey(key rune) bool {
+	if key < '1' || key > '0'+MAX_CHEATS {
+		return false
+	}
+	return c.engine.Toggle(int(key - '1'))
+}
+
+func (c *CheatMenu) Config() *display.Config {
+	return &c.config
+}
@@ -0,0 +1,100 @@
+package heatmap
+
+import (
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"time"
+
+	"github.com/colecrouter/gameboy-go/private/display"
+	"github.com/colecrouter/gameboy-go/private/profiler"
+)
+
+var viewNames = map[int]string{
+	profiler.ALL:     "all",
+	profiler.READ:    "reads",
+	profiler.WRITE:   "writes",
+	profiler.EXECUTE: "executes",
+}
+
+// HeatmapMenu shows how often each address is accessed. Each row is a 256-byte page, 0x0000 at the top.
+//
+//	o  start or stop recording
+//	n  switch between all accesses, reads, writes and executes
+//	e  export the counts to a CSV file in the working directory
+type HeatmapMenu struct {
+	config   display.Config
+	profiler *profiler.Profiler
+	view     int
+	img      image.Image
+}
+
+func NewHeatmapMenu(p *profiler.Profiler) *HeatmapMenu {
+	m := &HeatmapMenu{profiler: p, view: profiler.ALL}
+	m.config.Title = m.title()
+	return m
+}
+
+func (m *HeatmapMenu) title() string {
+	state := "stopped, o to record"
+	if m.profiler.Running() {
+		state = "recording"
+	}
+	return fmt.Sprintf("Heatmap (%s, %s)", viewNames[m.view], state)
+}
+
+func (m *HeatmapMenu) Clock() {
+	m.img = m.profiler.Heatmap(m.view)
+	m.config.Title = m.title()
+}
+
+func (m *HeatmapMenu) Image() image.Image {
+	if m.img == nil {
+		m.Clock()
+	}
+	return m.img
+}
+
+// HandleKey handles the menu's keys while it's open, returning false for keys it doesn't use.
+func (m *HeatmapMenu) HandleKey(key rune) bool {
+	switch key {
+	case 'o':
+		if m.profiler.Running() {
+			m.profiler.Stop()
+		} else {
+			m.profiler.Start()
+		}
+	case 'n':
+		m.view++
+		if m.view > profiler.EXECUTE {
+			m.view = profiler.ALL
+		}
+	case 'e':
+		m.export()
+	default:
+		return false
+	}
+	m.config.Title = m.title()
+	return true
+}
+
+func (m *HeatmapMenu) export() {
+	name := fmt.Sprintf("profile-%s.csv", time.Now().Format("20060102-150405"))
+	f, err := os.Create(name)
+	if err != nil {
+		log.Printf("exporting profile: %v", err)
+		return
+	}
+	defer f.Close()
+
+	if err := m.profiler.WriteCSV(f); err != nil {
+		log.Printf("exporting profile: %v", err)
+		return
+	}
+	log.Printf("Profile saved to %s", name)
+}
+
+func (m *HeatmapMenu) Config() *display.Config {
+	return &m.config
+}
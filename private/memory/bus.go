@@ -2,30 +2,168 @@ package memory
 
 import "fmt"
 
-type memoryMapping struct {
-	Start  uint16
-	End    uint16
-	Device Device
+// OPEN_BUS is read from addresses that nothing is mapped to.
+const OPEN_BUS = 0xFF
+
+const pageSize = 0x100
+
+// entry is where accesses to a page, or a single address in a split page, go.
+type entry struct {
+	device Device
+	start  uint16 // Where the device is mapped, since devices take relative addresses
+	ram    []byte // Buffer of plain RAM, accessed directly instead of through the device. Indexed by addr - start
+
+	// Pages shared by several devices dispatch again on the low byte
+	split *[pageSize]entry
 }
 
+// Bus routes accesses to devices through a 256-entry page table, so every access is a constant-time lookup.
+// Plain Memory devices are accessed directly through their buffers.
 type Bus struct {
-	mapping []memoryMapping
+	pages   [0x10000 / pageSize]entry
+	hooks   hooks
+	masters []BusMaster
+}
+
+// BusMaster is something other than the CPU that can take over the bus, like OAM DMA.
+type BusMaster interface {
+	// Conflict reports whether the CPU is locked out of addr, and what it reads there instead.
+	Conflict(addr uint16) (value uint8, conflict bool)
+}
+
+// AddMaster registers a bus master, whose conflicts apply to Read, Fetch and Write but not Peek.
+func (b *Bus) AddMaster(m BusMaster) {
+	b.masters = append(b.masters, m)
+}
+
+// conflict returns what the CPU sees at addr if a bus master has locked it out.
+func (b *Bus) conflict(addr uint16) (uint8, bool) {
+	for _, m := range b.masters {
+		if v, ok := m.Conflict(addr); ok {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// load reads for the CPU, taking bus conflicts into account.
+func (b *Bus) load(addr uint16) byte {
+	if len(b.masters) != 0 {
+		if v, ok := b.conflict(addr); ok {
+			return v
+		}
+	}
+	return b.Peek(addr)
 }
 
+// AddDevice maps a device over start-end. Where mappings overlap, the one added first wins.
 func (b *Bus) AddDevice(start uint16, end uint16, device Device) {
-	b.mapping = append(b.mapping, memoryMapping{Start: start, End: end, Device: device})
+	e := entry{device: device, start: start}
+	if m, ok := device.(*Memory); ok && len(m.Buffer) > int(end-start) {
+		e.ram = m.Buffer
+	}
+
+	for page := int(start) / pageSize; page <= int(end)/pageSize; page++ {
+		pageStart, pageEnd := page*pageSize, page*pageSize+pageSize-1
+		p := &b.pages[page]
+
+		// Already taken by a device covering the whole page
+		if p.device != nil {
+			continue
+		}
+
+		// Whole page, and nothing mapped yet
+		if int(start) <= pageStart && int(end) >= pageEnd && p.split == nil {
+			*p = e
+			continue
+		}
+
+		// Part of the page, or the page is shared, so split it
+		if p.split == nil {
+			p.split = new([pageSize]entry)
+		}
+		for addr := max(pageStart, int(start)); addr <= min(pageEnd, int(end)); addr++ {
+			if p.split[addr%pageSize].device == nil {
+				p.split[addr%pageSize] = e
+			}
+		}
+	}
 }
 
-func (b *Bus) Read(addr uint16) byte {
-	for _, mapping := range b.mapping {
-		if addr >= mapping.Start && addr <= mapping.End {
-			// Get adjusted address
-			addr -= mapping.Start
+// AddMirror makes start-end an alias of the range beginning at target, like echo RAM.
+// The target range must already be mapped; accesses go to whichever devices are mapped there.
+func (b *Bus) AddMirror(start uint16, end uint16, target uint16) {
+	offset := start - target
 
-			return mapping.Device.Read(addr)
+	for page := int(start) / pageSize; page <= int(end)/pageSize; page++ {
+		pageStart, pageEnd := page*pageSize, page*pageSize+pageSize-1
+		p := &b.pages[page]
+		if p.device != nil {
+			continue
+		}
+
+		// Whole page mirroring a whole page can share its entry
+		src := b.pages[(uint16(pageStart)-offset)/pageSize]
+		if int(start) <= pageStart && int(end) >= pageEnd && offset%pageSize == 0 && src.split == nil && p.split == nil {
+			if src.device != nil {
+				src.start += offset
+				*p = src
+			}
+			continue
 		}
+
+		if p.split == nil {
+			p.split = new([pageSize]entry)
+		}
+		for addr := max(pageStart, int(start)); addr <= min(pageEnd, int(end)); addr++ {
+			dst := &p.split[addr%pageSize]
+			if src := b.lookup(uint16(addr) - offset); dst.device == nil && src != nil {
+				*dst = *src
+				dst.start += offset
+			}
+		}
+	}
+}
+
+// lookup returns the entry handling addr, or nil if nothing is mapped there.
+func (b *Bus) lookup(addr uint16) *entry {
+	e := &b.pages[addr/pageSize]
+	if e.split != nil {
+		e = &e.split[addr%pageSize]
+	}
+	if e.device == nil {
+		return nil
+	}
+	return e
+}
+
+func (b *Bus) Read(addr uint16) byte {
+	val := b.load(addr)
+	if set := b.hooks.set.Load(); set != nil {
+		b.fire(set, ACCESS_READ, addr, val, val)
+	}
+	return val
+}
+
+// Fetch reads an opcode for the CPU to execute. It's a read, but triggers execute hooks instead of read hooks.
+func (b *Bus) Fetch(addr uint16) byte {
+	val := b.load(addr)
+	if set := b.hooks.set.Load(); set != nil {
+		b.fire(set, ACCESS_EXECUTE, addr, val, val)
 	}
-	panic("No device found for address")
+	return val
+}
+
+// Peek reads without triggering hooks or bus conflicts, for debuggers and the hooks themselves.
+func (b *Bus) Peek(addr uint16) byte {
+	e := b.lookup(addr)
+	if e == nil {
+		return OPEN_BUS
+	}
+	if e.ram != nil {
+		return e.ram[addr-e.start]
+	}
+	return e.device.Read(addr - e.start)
 }
 
 // func (b *Bus) Read16(addr uint16) (high, low uint8) {
@@ -36,15 +174,41 @@ func (b *Bus) Read(addr uint16) byte {
 // }
 
 func (b *Bus) Write(addr uint16, data byte) {
-	for _, mapping := range b.mapping {
-		if addr >= mapping.Start && addr <= mapping.End {
-			// Get adjusted address
-			addr -= mapping.Start
-
-			mapping.Device.Write(addr, data)
+	if len(b.masters) != 0 {
+		if _, ok := b.conflict(addr); ok {
 			return
 		}
 	}
+
+	set := b.hooks.set.Load()
+	if set == nil {
+		b.Poke(addr, data)
+		return
+	}
+
+	// Only read the old value when a hook wants it, since reading a device can have side effects
+	var old uint8
+	hooked := set.covers(ACCESS_WRITE, addr)
+	if hooked {
+		old = b.Peek(addr)
+	}
+	b.Poke(addr, data)
+	if hooked {
+		b.fire(set, ACCESS_WRITE, addr, old, data)
+	}
+}
+
+// Poke writes without triggering hooks or bus conflicts, for debuggers and cheats.
+func (b *Bus) Poke(addr uint16, data byte) {
+	e := b.lookup(addr)
+	if e == nil {
+		return
+	}
+	if e.ram != nil {
+		e.ram[addr-e.start] = data
+		return
+	}
+	e.device.Write(addr-e.start, data)
 }
 
 // func (b *Bus) Write16(addr uint16, data uint16) {
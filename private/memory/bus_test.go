@@ -0,0 +1,231 @@
+package memory
+
+import "testing"
+
+type memoryMapping struct {
+	Start  uint16
+	End    uint16
+	Device Device
+}
+
+// linearBus is the bus before it had a page table, kept as a baseline for the benchmarks.
+type linearBus struct {
+	mapping []memoryMapping
+}
+
+func (b *linearBus) AddDevice(start uint16, end uint16, device Device) {
+	b.mapping = append(b.mapping, memoryMapping{Start: start, End: end, Device: device})
+}
+
+func (b *linearBus) Read(addr uint16) byte {
+	for _, mapping := range b.mapping {
+		if addr >= mapping.Start && addr <= mapping.End {
+			return mapping.Device.Read(addr - mapping.Start)
+		}
+	}
+	panic("No device found for address")
+}
+
+func (b *linearBus) Write(addr uint16, data byte) {
+	for _, mapping := range b.mapping {
+		if addr >= mapping.Start && addr <= mapping.End {
+			mapping.Device.Write(addr-mapping.Start, data)
+			return
+		}
+	}
+}
+
+// register is a device that isn't plain RAM, so it can't take the fast path.
+type register struct {
+	values map[uint16]uint8
+}
+
+func (r *register) Read(addr uint16) uint8 {
+	return r.values[addr]
+}
+
+func (r *register) Write(addr uint16, data uint8) {
+	if r.values == nil {
+		r.values = map[uint16]uint8{}
+	}
+	r.values[addr] = data
+}
+
+type mapper interface {
+	AddDevice(start, end uint16, device Device)
+}
+
+// addGameBoyMap maps devices the way the Game Boy does, including the pages split between devices at 0xFE00-0xFFFF.
+func addGameBoyMap(b mapper) {
+	b.AddDevice(0x0000, 0x7FFF, &register{})                           // Cartridge
+	b.AddDevice(0x8000, 0x9FFF, &Memory{Buffer: make([]byte, 0x2000)}) // VRAM
+	b.AddDevice(0xA000, 0xBFFF, &register{})                           // External RAM
+	b.AddDevice(0xC000, 0xCFFF, &Memory{Buffer: make([]byte, 0x1000)}) // WRAM
+	b.AddDevice(0xD000, 0xDFFF, &Memory{Buffer: make([]byte, 0x1000)}) // WRAM
+	b.AddDevice(0xE000, 0xFDFF, &Memory{Buffer: make([]byte, 0x1E00)}) // Echo RAM
+	b.AddDevice(0xFE00, 0xFE9F, &Memory{Buffer: make([]byte, 0xA0)})   // OAM
+	b.AddDevice(0xFF00, 0xFF7F, &register{})                           // I/O
+	b.AddDevice(0xFF80, 0xFFFE, &Memory{Buffer: make([]byte, 0x7F)})   // High RAM
+	b.AddDevice(0xFFFF, 0xFFFF, &register{})                           // IE
+}
+
+func TestBusMatchesLinear(t *testing.T) {
+	paged := &Bus{}
+	linear := &linearBus{}
+	addGameBoyMap(paged)
+	addGameBoyMap(linear)
+
+	for addr := range 0x10000 {
+		// 0xFEA0-0xFEFF is left unmapped, which the old bus panicked on
+		if addr >= 0xFEA0 && addr <= 0xFEFF {
+			continue
+		}
+		paged.Write(uint16(addr), uint8(addr*7))
+		linear.Write(uint16(addr), uint8(addr*7))
+	}
+
+	for addr := range 0x10000 {
+		if addr >= 0xFEA0 && addr <= 0xFEFF {
+			continue
+		}
+		if got, want := paged.Read(uint16(addr)), linear.Read(uint16(addr)); got != want {
+			t.Fatalf("Read(%04X) = %02X, want %02X", addr, got, want)
+		}
+	}
+}
+
+func TestBusOpenBus(t *testing.T) {
+	b := &Bus{}
+	b.AddDevice(0xFE00, 0xFE9F, &Memory{Buffer: make([]byte, 0xA0)})
+
+	b.Write(0xFEA0, 0x12)
+	for _, addr := range []uint16{0x0000, 0xFD00, 0xFEA0, 0xFEFF, 0xFFFF} {
+		if got := b.Read(addr); got != OPEN_BUS {
+			t.Errorf("Read(%04X) = %02X, want open bus", addr, got)
+		}
+	}
+}
+
+func TestBusRelativeAddressing(t *testing.T) {
+	b := &Bus{}
+	reg := &register{}
+	ram := &Memory{Buffer: make([]byte, 0x10)}
+	b.AddDevice(0xFF00, 0xFF0F, reg)
+	b.AddDevice(0xFF10, 0xFF1F, ram)
+
+	b.Write(0xFF05, 0xAA)
+	b.Write(0xFF15, 0xBB)
+	if reg.values[0x05] != 0xAA {
+		t.Errorf("device saw %v, want relative address 05", reg.values)
+	}
+	if ram.Buffer[0x05] != 0xBB {
+		t.Errorf("RAM buffer %X, want BB at 05", ram.Buffer)
+	}
+}
+
+func TestBusFirstMappingWins(t *testing.T) {
+	b := &Bus{}
+	first := &Memory{Buffer: make([]byte, 0x200)}
+	second := &Memory{Buffer: make([]byte, 0x200)}
+	first.Buffer[0x150] = 1
+	second.Buffer[0x50] = 2
+	b.AddDevice(0x0000, 0x01FF, first)
+	b.AddDevice(0x0100, 0x02FF, second)
+
+	if got := b.Read(0x0150); got != 1 {
+		t.Errorf("overlapping read = %d, want the first device", got)
+	}
+	if got := b.Read(0x0250); got != 0 {
+		t.Errorf("got %d, want the second device's 0x150", got)
+	}
+}
+
+// Addresses spread over the map, weighted towards the regions games use most.
+var benchAddrs = []uint16{0x0150, 0x4321, 0xC123, 0xD456, 0xFF44, 0xFF85, 0x9800, 0xA010, 0xFE10, 0xFFFF, 0xC800, 0x0038}
+
+func BenchmarkBusRead(b *testing.B) {
+	b.Run("Paged", func(b *testing.B) {
+		bus := &Bus{}
+		addGameBoyMap(bus)
+		for i := 0; b.Loop(); i++ {
+			bus.Read(benchAddrs[i%len(benchAddrs)])
+		}
+	})
+	b.Run("Linear", func(b *testing.B) {
+		bus := &linearBus{}
+		addGameBoyMap(bus)
+		for i := 0; b.Loop(); i++ {
+			bus.Read(benchAddrs[i%len(benchAddrs)])
+		}
+	})
+}
+
+func BenchmarkBusWrite(b *testing.B) {
+	b.Run("Paged", func(b *testing.B) {
+		bus := &Bus{}
+		addGameBoyMap(bus)
+		for i := 0; b.Loop(); i++ {
+			bus.Write(benchAddrs[i%len(benchAddrs)], uint8(i))
+		}
+	})
+	b.Run("Linear", func(b *testing.B) {
+		bus := &linearBus{}
+		addGameBoyMap(bus)
+		for i := 0; b.Loop(); i++ {
+			bus.Write(benchAddrs[i%len(benchAddrs)], uint8(i))
+		}
+	})
+}
+
+func TestBusMirror(t *testing.T) {
+	b := &Bus{}
+	wram0 := &Memory{Buffer: make([]byte, 0x1000)}
+	wram1 := &register{}
+	b.AddDevice(0xC000, 0xCFFF, wram0)
+	b.AddDevice(0xD000, 0xDFFF, wram1)
+	b.AddDevice(0xFE00, 0xFE9F, &Memory{Buffer: make([]byte, 0xA0)})
+	b.AddMirror(0xE000, 0xFDFF, 0xC000)
+
+	tests := []struct {
+		echo, wram uint16
+	}{
+		{0xE000, 0xC000},
+		{0xEFFF, 0xCFFF},
+		{0xF000, 0xD000},
+		{0xFD42, 0xDD42},
+		{0xFDFF, 0xDDFF},
+	}
+
+	for i, tt := range tests {
+		b.Write(tt.echo, uint8(i+1))
+		if got := b.Read(tt.wram); got != uint8(i+1) {
+			t.Errorf("write to %04X: WRAM %04X = %02X, want %02X", tt.echo, tt.wram, got, i+1)
+		}
+		b.Write(tt.wram, uint8(i+0x80))
+		if got := b.Read(tt.echo); got != uint8(i+0x80) {
+			t.Errorf("write to %04X: echo %04X = %02X, want %02X", tt.wram, tt.echo, got, i+0x80)
+		}
+	}
+
+	// The mirror stops at 0xFDFF, where OAM takes over
+	b.Write(0xFE00, 0x55)
+	if got := b.Read(0xDE00); got == 0x55 {
+		t.Error("OAM write leaked into WRAM")
+	}
+}
+
+func TestBusMirrorUnaligned(t *testing.T) {
+	b := &Bus{}
+	ram := &Memory{Buffer: make([]byte, 0x200)}
+	b.AddDevice(0x1000, 0x11FF, ram)
+	b.AddMirror(0x2080, 0x227F, 0x1000)
+
+	b.Write(0x2080, 0x01)
+	b.Write(0x227F, 0x02)
+	if ram.Buffer[0x000] != 0x01 || ram.Buffer[0x1FF] != 0x02 {
+		t.Errorf("mirror writes landed at the wrong offsets")
+	}
+	if got := b.Read(0x2000); got != OPEN_BUS {
+		t.Errorf("Read(2000) = %02X, want open bus outside the mirror", got)
+	}
+}
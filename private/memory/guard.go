@@ -0,0 +1,27 @@
+package memory
+
+// Guarded blocks access to a device while it's in use by something else, like VRAM during pixel transfer.
+// Blocked reads return 0xFF and blocked writes are dropped.
+type Guarded struct {
+	device  Device
+	blocked func() bool
+}
+
+// NewGuarded wraps a device so it can't be accessed while blocked returns true.
+func NewGuarded(device Device, blocked func() bool) *Guarded {
+	return &Guarded{device: device, blocked: blocked}
+}
+
+func (g *Guarded) Read(addr uint16) uint8 {
+	if g.blocked() {
+		return 0xFF
+	}
+	return g.device.Read(addr)
+}
+
+func (g *Guarded) Write(addr uint16, data uint8) {
+	if g.blocked() {
+		return
+	}
+	g.device.Write(addr, data)
+}
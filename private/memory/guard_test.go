@@ -0,0 +1,28 @@
+package memory
+
+import "testing"
+
+func TestGuarded(t *testing.T) {
+	blocked := false
+	ram := &Memory{Buffer: make([]byte, 0x10)}
+	g := NewGuarded(ram, func() bool { return blocked })
+
+	g.Write(0x01, 0x42)
+	if got := g.Read(0x01); got != 0x42 {
+		t.Errorf("unblocked read = %02X, want 42", got)
+	}
+
+	blocked = true
+	if got := g.Read(0x01); got != 0xFF {
+		t.Errorf("blocked read = %02X, want FF", got)
+	}
+	g.Write(0x01, 0x24)
+	if ram.Buffer[0x01] != 0x42 {
+		t.Error("blocked write went through")
+	}
+
+	blocked = false
+	if got := g.Read(0x01); got != 0x42 {
+		t.Errorf("read after unblocking = %02X, want 42", got)
+	}
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AccessType is a kind of bus access. Values can be combined to hook several kinds at once.
+type AccessType uint8
+
+const (
+	ACCESS_READ AccessType = 1 << iota
+	ACCESS_WRITE
+	ACCESS_EXECUTE // Opcode fetches
+)
+
+// Access describes a bus access seen by a hook.
+type Access struct {
+	Type  AccessType
+	Addr  uint16
+	Old   uint8  // Value before a write. For reads and executes, the value read
+	New   uint8  // Value written. For reads and executes, the value read
+	PC    uint16 // Address of the instruction making the access
+	Cycle uint64 // T-cycles since power on
+}
+
+// Hook calls Func for accesses of the given types to Start-End.
+// If Filter is set, only accesses it returns true for are passed on.
+type Hook struct {
+	Types  AccessType
+	Start  uint16
+	End    uint16
+	Filter func(a Access) bool
+	Func   func(a Access)
+}
+
+// HookID identifies a registered hook, so it can be removed.
+type HookID uint64
+
+// ValueIs is a hook filter for accesses that read or write the given value.
+func ValueIs(v uint8) func(a Access) bool {
+	return func(a Access) bool {
+		return a.New == v
+	}
+}
+
+// ValueChanged is a hook filter for writes that change the stored value.
+func ValueChanged(a Access) bool {
+	return a.Old != a.New
+}
+
+type registeredHook struct {
+	id HookID
+	Hook
+}
+
+// hookSet is the registered hooks. It's replaced, never modified, so accesses can read it without locking.
+type hookSet struct {
+	hooks []registeredHook
+	types AccessType // Union of all the hooks' types
+}
+
+type hooks struct {
+	mu     sync.Mutex
+	set    atomic.Pointer[hookSet] // Nil while no hooks are registered, so accesses skip them with one load
+	nextID HookID
+
+	pc     func() uint16
+	cycles func() uint64
+}
+
+// SetTracer sets where hooks get the PC and cycle count from.
+func (b *Bus) SetTracer(pc func() uint16, cycles func() uint64) {
+	b.hooks.mu.Lock()
+	defer b.hooks.mu.Unlock()
+	b.hooks.pc = pc
+	b.hooks.cycles = cycles
+}
+
+// AddHook registers a hook, returning an ID to remove it with.
+// Hooks run on the emulation goroutine, in the middle of the access, so they should be quick.
+// Use Peek to read memory from a hook without triggering hooks again.
+func (b *Bus) AddHook(h Hook) HookID {
+	b.hooks.mu.Lock()
+	defer b.hooks.mu.Unlock()
+
+	b.hooks.nextID++
+	id := b.hooks.nextID
+
+	set := &hookSet{}
+	if old := b.hooks.set.Load(); old != nil {
+		set.hooks = append(set.hooks, old.hooks...)
+		set.types = old.types
+	}
+	set.hooks = append(set.hooks, registeredHook{id: id, Hook: h})
+	set.types |= h.Types
+	b.hooks.set.Store(set)
+
+	return id
+}
+
+// RemoveHook unregisters a hook. Removing a hook that isn't registered does nothing.
+func (b *Bus) RemoveHook(id HookID) {
+	b.hooks.mu.Lock()
+	defer b.hooks.mu.Unlock()
+
+	old := b.hooks.set.Load()
+	if old == nil {
+		return
+	}
+
+	set := &hookSet{}
+	for _, h := range old.hooks {
+		if h.id != id {
+			set.hooks = append(set.hooks, h)
+			set.types |= h.Types
+		}
+	}
+	if len(set.hooks) == 0 {
+		b.hooks.set.Store(nil)
+		return
+	}
+	b.hooks.set.Store(set)
+}
+
+// covers reports whether any hook of type t covers addr.
+func (s *hookSet) covers(t AccessType, addr uint16) bool {
+	if s.types&t == 0 {
+		return false
+	}
+	for _, h := range s.hooks {
+		if h.Types&t != 0 && addr >= h.Start && addr <= h.End {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bus) fire(s *hookSet, t AccessType, addr uint16, old, new uint8) {
+	if s.types&t == 0 {
+		return
+	}
+
+	a := Access{Type: t, Addr: addr, Old: old, New: new}
+	if b.hooks.pc != nil {
+		a.PC = b.hooks.pc()
+	}
+	if b.hooks.cycles != nil {
+		a.Cycle = b.hooks.cycles()
+	}
+
+	for _, h := range s.hooks {
+		if h.Types&t == 0 || addr < h.Start || addr > h.End {
+			continue
+		}
+		if h.Filter != nil && !h.Filter(a) {
+			continue
+		}
+		h.Func(a)
+	}
+}
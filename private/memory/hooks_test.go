@@ -0,0 +1,125 @@
+package memory
+
+import "testing"
+
+func newHookedBus() *Bus {
+	b := &Bus{}
+	b.AddDevice(0xC000, 0xDFFF, &Memory{Buffer: make([]byte, 0x2000)})
+	b.AddDevice(0xFF00, 0xFF7F, &register{})
+	b.SetTracer(func() uint16 { return 0x0150 }, func() uint64 { return 1234 })
+	return b
+}
+
+func TestHooks(t *testing.T) {
+	t.Run("Read", func(t *testing.T) {
+		b := newHookedBus()
+		b.Write(0xC010, 0x42)
+
+		var got []Access
+		b.AddHook(Hook{Types: ACCESS_READ, Start: 0xC000, End: 0xC0FF, Func: func(a Access) { got = append(got, a) }})
+
+		b.Read(0xC010)
+		b.Read(0xC100) // Outside the range
+		b.Write(0xC010, 0x43)
+
+		want := Access{Type: ACCESS_READ, Addr: 0xC010, Old: 0x42, New: 0x42, PC: 0x0150, Cycle: 1234}
+		if len(got) != 1 || got[0] != want {
+			t.Errorf("got %+v, want [%+v]", got, want)
+		}
+	})
+
+	t.Run("Write", func(t *testing.T) {
+		b := newHookedBus()
+		b.Write(0xFF05, 0x10)
+
+		var got []Access
+		b.AddHook(Hook{Types: ACCESS_WRITE, Start: 0xFF05, End: 0xFF05, Func: func(a Access) { got = append(got, a) }})
+
+		b.Write(0xFF05, 0x11)
+		b.Read(0xFF05)
+
+		if len(got) != 1 || got[0].Old != 0x10 || got[0].New != 0x11 || got[0].Type != ACCESS_WRITE {
+			t.Errorf("got %+v, want one write from 10 to 11", got)
+		}
+	})
+
+	t.Run("Execute", func(t *testing.T) {
+		b := newHookedBus()
+
+		var reads, executes int
+		b.AddHook(Hook{Types: ACCESS_READ, Start: 0xC000, End: 0xDFFF, Func: func(Access) { reads++ }})
+		b.AddHook(Hook{Types: ACCESS_EXECUTE, Start: 0xC000, End: 0xDFFF, Func: func(Access) { executes++ }})
+
+		b.Fetch(0xC000)
+		b.Fetch(0xC001)
+		b.Read(0xC002)
+
+		if executes != 2 || reads != 1 {
+			t.Errorf("got %d executes and %d reads, want 2 and 1", executes, reads)
+		}
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		b := newHookedBus()
+
+		var matched, changed int
+		b.AddHook(Hook{Types: ACCESS_WRITE, Start: 0xC000, End: 0xC000, Filter: ValueIs(0x99), Func: func(Access) { matched++ }})
+		b.AddHook(Hook{Types: ACCESS_WRITE, Start: 0xC000, End: 0xC000, Filter: ValueChanged, Func: func(Access) { changed++ }})
+
+		for _, v := range []uint8{0x01, 0x01, 0x99, 0x99, 0x02} {
+			b.Write(0xC000, v)
+		}
+
+		if matched != 2 {
+			t.Errorf("ValueIs matched %d writes, want 2", matched)
+		}
+		if changed != 3 {
+			t.Errorf("ValueChanged matched %d writes, want 3", changed)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		b := newHookedBus()
+
+		var calls int
+		id := b.AddHook(Hook{Types: ACCESS_READ | ACCESS_WRITE, Start: 0x0000, End: 0xFFFF, Func: func(Access) { calls++ }})
+		b.Read(0xC000)
+		b.RemoveHook(id)
+		b.RemoveHook(id)
+		b.Read(0xC000)
+		b.Write(0xC000, 0)
+
+		if calls != 1 {
+			t.Errorf("got %d calls, want 1", calls)
+		}
+		if b.hooks.set.Load() != nil {
+			t.Error("hook set not cleared after removing the last hook")
+		}
+	})
+
+	t.Run("Peek", func(t *testing.T) {
+		b := newHookedBus()
+		b.AddHook(Hook{Types: ACCESS_READ, Start: 0x0000, End: 0xFFFF, Func: func(Access) { t.Error("Peek triggered a hook") }})
+		b.Peek(0xC000)
+	})
+}
+
+func TestHooksNoAllocations(t *testing.T) {
+	b := newHookedBus()
+	allocs := testing.AllocsPerRun(100, func() {
+		b.Write(0xC000, b.Read(0xC001))
+		b.Fetch(0xC002)
+	})
+	if allocs != 0 {
+		t.Errorf("accesses without hooks allocated %v times", allocs)
+	}
+}
+
+func BenchmarkBusReadHooked(b *testing.B) {
+	bus := &Bus{}
+	addGameBoyMap(bus)
+	bus.AddHook(Hook{Types: ACCESS_WRITE, Start: 0xC000, End: 0xC000, Func: func(Access) {}})
+	for i := 0; b.Loop(); i++ {
+		bus.Read(benchAddrs[i%len(benchAddrs)])
+	}
+}
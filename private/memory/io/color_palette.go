@@ -0,0 +1,53 @@
+package io
+
+// COLOR_PALETTE_SIZE is the size of each CGB palette RAM: 8 palettes of 4 colors, 2 bytes each.
+const COLOR_PALETTE_SIZE = 64
+
+// ColorPalettes is one CGB palette RAM and its index register, BCPS/BCPD for the background or OCPS/OCPD for sprites.
+// Colors are little-endian RGB555.
+type ColorPalettes struct {
+	RAM           [COLOR_PALETTE_SIZE]uint8
+	Index         uint8 // Bits 0-5 - Byte accessed through the data register
+	AutoIncrement bool  // Bit 7 - Move to the next byte after each data write
+}
+
+func (p *ColorPalettes) Read(addr uint16) uint8 {
+	switch addr {
+	case 0:
+		val := 0x40 | p.Index
+		if p.AutoIncrement {
+			val |= 1 << 7
+		}
+		return val
+	case 1:
+		return p.RAM[p.Index]
+	default:
+		panic("Invalid address")
+	}
+}
+
+func (p *ColorPalettes) Write(addr uint16, value uint8) {
+	switch addr {
+	case 0:
+		p.Index = value & 0x3F
+		p.AutoIncrement = value&(1<<7) != 0
+	case 1:
+		p.RAM[p.Index] = value
+		p.increment()
+	default:
+		panic("Invalid address")
+	}
+}
+
+// increment advances the index after a data write, if auto-increment is on. It wraps within the palette RAM.
+func (p *ColorPalettes) increment() {
+	if p.AutoIncrement {
+		p.Index = (p.Index + 1) & 0x3F
+	}
+}
+
+// Color returns a color of a palette as RGB555.
+func (p *ColorPalettes) Color(palette, color int) uint16 {
+	i := palette*8 + color*2
+	return (uint16(p.RAM[i]) | uint16(p.RAM[i+1])<<8) & 0x7FFF
+}
@@ -0,0 +1,56 @@
+package io
+
+import "testing"
+
+func TestColorPalettes(t *testing.T) {
+	r := NewRegisters(nil, nil, &Interrupt{})
+
+	// Locked outside CGB mode
+	r.Write(0x68, 0x80)
+	if got := r.Read(0x68); got != 0xFF {
+		t.Errorf("BCPS in DMG mode = %02X, want FF", got)
+	}
+
+	r.CGBMode = true
+
+	// Write BG palette 1 color 0 with auto-increment
+	r.Write(0x68, 0x80|0x08)
+	r.Write(0x69, 0x1F)
+	r.Write(0x69, 0x00)
+	if got := r.Read(0x68); got != 0xC0|0x0A {
+		t.Errorf("BCPS = %02X, want CA", got)
+	}
+	if got := r.BGPalettes.Color(1, 0); got != 0x001F {
+		t.Errorf("BG palette 1 color 0 = %04X, want 001F", got)
+	}
+
+	// Without auto-increment, the index stays put
+	r.Write(0x6A, 0x3F)
+	r.Write(0x6B, 0x12)
+	r.Write(0x6B, 0x7C)
+	if got := r.Read(0x6B); got != 0x7C {
+		t.Errorf("OCPD = %02X, want 7C", got)
+	}
+	if got := r.OBJPalettes.Color(7, 3); got != 0x7C00 {
+		t.Errorf("OBJ palette 7 color 3 = %04X, want 7C00", got)
+	}
+
+	// The index wraps
+	r.Write(0x6A, 0xBF)
+	r.Write(0x6B, 0x01)
+	if got := r.Read(0x6A); got != 0xC0 {
+		t.Errorf("OCPS after wrap = %02X, want C0", got)
+	}
+
+	// Locked during pixel transfer, but the index still increments
+	r.LCDControl.EnableLCD = true
+	r.LCDStatus.PPUMode = Drawing
+	r.Write(0x68, 0x80)
+	r.Write(0x69, 0x55)
+	if got := r.Read(0x69); got != 0xFF {
+		t.Errorf("BCPD during pixel transfer = %02X, want FF", got)
+	}
+	if r.BGPalettes.RAM[0] != 0 || r.BGPalettes.Index != 1 {
+		t.Errorf("blocked write: RAM[0] = %02X, index = %d", r.BGPalettes.RAM[0], r.BGPalettes.Index)
+	}
+}
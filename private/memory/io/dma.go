@@ -0,0 +1,115 @@
+package io
+
+import (
+	"github.com/colecrouter/gameboy-go/private/memory"
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+const (
+	oamSize = 0xA0
+
+	// M-cycles from the write to 0xFF46 until the first byte is copied: the write itself, then one of setup
+	dmaStartDelay = 2
+)
+
+// OAMDMA copies 160 bytes to OAM, one per M-cycle, after a write to 0xFF46.
+// While it runs, the CPU can only reach HRAM and the I/O registers; reads anywhere else see the byte being copied.
+// https://gbdev.io/pandocs/OAM_DMA_Transfer.html
+type OAMDMA struct {
+	bus memory.Device
+	oam memory.Device
+
+	source  uint16 // Start of the running transfer
+	index   uint16 // Next byte to copy
+	active  bool
+	pending int // M-cycles until a requested transfer starts
+	next    uint16
+
+	clock    <-chan struct{}
+	clockAck chan<- struct{}
+}
+
+func NewOAMDMA(broadcaster *system.Broadcaster, bus memory.Device) *OAMDMA {
+	d := &OAMDMA{bus: bus}
+	if broadcaster != nil {
+		// Copy on the falling edge, so the CPU sees a consistent state on the rising edge
+		d.clock, d.clockAck = broadcaster.Subscribe(system.MFallingEdge)
+	}
+	return d
+}
+
+// Start requests a transfer from page << 8. A transfer already running carries on until the new one starts.
+func (d *OAMDMA) Start(page uint8) {
+	source := uint16(page) << 8
+	// 0xE000 and up isn't wired to the DMA, it sees WRAM instead
+	if source >= 0xE000 {
+		source -= 0x2000
+	}
+	d.next = source
+	d.pending = dmaStartDelay
+}
+
+// Active reports whether a transfer is copying bytes, and so holding the bus.
+func (d *OAMDMA) Active() bool {
+	return d.active
+}
+
+// MClock advances the transfer by one M-cycle.
+func (d *OAMDMA) MClock() {
+	if d.active {
+		d.write(d.index, dmaRead(d.bus, d.source+d.index))
+		d.index++
+		if d.index == oamSize {
+			d.active = false
+		}
+	}
+
+	if d.pending > 0 {
+		d.pending--
+		if d.pending == 0 {
+			d.source = d.next
+			d.index = 0
+			d.active = true
+		}
+	}
+}
+
+// Conflict implements memory.BusMaster.
+func (d *OAMDMA) Conflict(addr uint16) (uint8, bool) {
+	if !d.active || addr >= 0xFF00 {
+		return 0, false
+	}
+	if addr >= 0xFE00 {
+		// OAM is busy being written
+		return 0xFF, true
+	}
+	return dmaRead(d.bus, d.source+d.index), true
+}
+
+// dmaRead reads a DMA source byte. It goes around bus conflicts and hooks where it can, since those are for the CPU.
+func dmaRead(bus memory.Device, addr uint16) uint8 {
+	if p, ok := bus.(interface{ Peek(uint16) uint8 }); ok {
+		return p.Peek(addr)
+	}
+	return bus.Read(addr)
+}
+
+func (d *OAMDMA) write(offset uint16, value uint8) {
+	if d.oam != nil {
+		d.oam.Write(offset, value)
+		return
+	}
+	d.bus.Write(0xFE00+offset, value)
+}
+
+func (d *OAMDMA) Run(close <-chan struct{}) {
+	for {
+		select {
+		case <-close:
+			return
+		case <-d.clock:
+			d.MClock()
+			d.clockAck <- struct{}{}
+		}
+	}
+}
@@ -2,6 +2,8 @@ package io
 
 import (
 	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/memory"
 )
 
 // FakeBus implements minimal Bus behavior for testing.
@@ -20,6 +22,13 @@ func (fb *FakeBus) Write(addr uint16, value uint8) {
 	fb.mem[addr] = value
 }
 
+// clockDMA runs the DMA for n M-cycles.
+func clockDMA(r *Registers, n int) {
+	for range n {
+		r.DMATransfer.MClock()
+	}
+}
+
 func TestDMA(t *testing.T) {
 	// Prepopulate FakeBus memory for DMA source (0x8000-0x80A0).
 	fakeBus := &FakeBus{mem: make(map[uint16]uint8)}
@@ -37,6 +46,9 @@ func TestDMA(t *testing.T) {
 	// Trigger DMA transfer; passing 0x80 -> source = 0x80 << 8 = 0x8000.
 	regs.Write(0x46, 0x80)
 
+	// The write cycle and one setup cycle, then one byte per M-cycle.
+	clockDMA(regs, dmaStartDelay+dmaSize)
+
 	// Verify that DMA copied dmaSize bytes from source to destination.
 	for i := 0; i < dmaSize; i++ {
 		expected := fakeBus.mem[sourceBase+uint16(i)]
@@ -46,3 +58,174 @@ func TestDMA(t *testing.T) {
 		}
 	}
 }
+
+func TestDMAConnectedOAM(t *testing.T) {
+	fakeBus := &FakeBus{mem: make(map[uint16]uint8)}
+	oam := &FakeBus{mem: make(map[uint16]uint8)}
+	for i := 0; i < 0xA0; i++ {
+		fakeBus.mem[0xC000+uint16(i)] = uint8(i) ^ 0x5A
+	}
+
+	regs := NewRegisters(nil, fakeBus, nil)
+	regs.ConnectOAM(oam)
+	regs.Write(0x46, 0xC0)
+	clockDMA(regs, dmaStartDelay+oamSize)
+
+	for i := 0; i < 0xA0; i++ {
+		if got, want := oam.mem[uint16(i)], uint8(i)^0x5A; got != want {
+			t.Errorf("OAM offset %d: expected 0x%02X, got 0x%02X", i, want, got)
+		}
+		if _, ok := fakeBus.mem[0xFE00+uint16(i)]; ok {
+			t.Fatalf("DMA wrote OAM through the bus at offset %d", i)
+		}
+	}
+}
+
+func TestDMATiming(t *testing.T) {
+	fakeBus := &FakeBus{mem: make(map[uint16]uint8)}
+	oam := &FakeBus{mem: make(map[uint16]uint8)}
+	for i := 0; i < oamSize; i++ {
+		fakeBus.mem[0xC000+uint16(i)] = uint8(i) + 1
+	}
+
+	regs := NewRegisters(nil, fakeBus, nil)
+	regs.ConnectOAM(oam)
+	dma := &regs.DMATransfer
+
+	regs.Write(0x46, 0xC0)
+	if regs.Read(0x46) != 0xC0 {
+		t.Errorf("DMA register = %02X, want C0", regs.Read(0x46))
+	}
+
+	// Write cycle and setup cycle: the bus is still free
+	for cycle := range dmaStartDelay {
+		if dma.Active() {
+			t.Fatalf("DMA holding the bus %d cycles after the write", cycle)
+		}
+		dma.MClock()
+	}
+
+	// 160 cycles holding the bus, copying one byte each
+	for i := range oamSize {
+		if !dma.Active() {
+			t.Fatalf("DMA released the bus after %d bytes", i)
+		}
+		if v, ok := dma.Conflict(0x4000); !ok || v != uint8(i)+1 {
+			t.Errorf("conflict read during byte %d = %02X, %t, want %02X", i, v, ok, uint8(i)+1)
+		}
+		if len(oam.mem) != i {
+			t.Fatalf("copied %d bytes after %d cycles", len(oam.mem), i)
+		}
+		dma.MClock()
+	}
+
+	if dma.Active() {
+		t.Error("DMA still holding the bus after 160 bytes")
+	}
+	if len(oam.mem) != oamSize {
+		t.Errorf("copied %d bytes, want %d", len(oam.mem), oamSize)
+	}
+}
+
+func TestDMAConflicts(t *testing.T) {
+	bus := &memory.Bus{}
+	wram := &memory.Memory{Buffer: make([]byte, 0x2000)}
+	hram := &memory.Memory{Buffer: make([]byte, 0x7F)}
+	oam := &memory.Memory{Buffer: make([]byte, oamSize)}
+	bus.AddDevice(0xC000, 0xDFFF, wram)
+	bus.AddDevice(0xFE00, 0xFE9F, oam)
+	bus.AddDevice(0xFF80, 0xFFFE, hram)
+
+	regs := NewRegisters(nil, bus, nil)
+	regs.ConnectOAM(oam)
+	bus.AddDevice(0xFF00, 0xFF7F, regs)
+	bus.AddMaster(&regs.DMATransfer)
+
+	wram.Buffer[0x0000] = 0x11
+	wram.Buffer[0x0123] = 0x22
+	hram.Buffer[0x00] = 0x33
+
+	bus.Write(0xFF46, 0xC0)
+	clockDMA(regs, dmaStartDelay)
+
+	if got := bus.Read(0xC123); got != 0x11 {
+		t.Errorf("WRAM read during DMA = %02X, want the DMA's byte 11", got)
+	}
+	if got := bus.Read(0xFE00); got != 0xFF {
+		t.Errorf("OAM read during DMA = %02X, want FF", got)
+	}
+	if got := bus.Read(0xFF80); got != 0x33 {
+		t.Errorf("HRAM read during DMA = %02X, want 33", got)
+	}
+	if got := bus.Read(0xFF46); got != 0xC0 {
+		t.Errorf("DMA register read during DMA = %02X, want C0", got)
+	}
+	if got := bus.Peek(0xC123); got != 0x22 {
+		t.Errorf("Peek during DMA = %02X, want 22", got)
+	}
+
+	bus.Write(0xC123, 0x44)
+	bus.Write(0xFF81, 0x55)
+	if wram.Buffer[0x0123] != 0x22 {
+		t.Error("WRAM write during DMA went through")
+	}
+	if hram.Buffer[0x01] != 0x55 {
+		t.Error("HRAM write during DMA was dropped")
+	}
+
+	clockDMA(regs, oamSize)
+	if got := bus.Read(0xC123); got != 0x22 {
+		t.Errorf("WRAM read after DMA = %02X, want 22", got)
+	}
+	if oam.Buffer[0] != 0x11 {
+		t.Errorf("OAM[0] = %02X, want 11", oam.Buffer[0])
+	}
+}
+
+func TestDMARestart(t *testing.T) {
+	fakeBus := &FakeBus{mem: make(map[uint16]uint8)}
+	oam := &FakeBus{mem: make(map[uint16]uint8)}
+	for i := range uint16(oamSize) {
+		fakeBus.mem[0xC000+i] = 0xAA
+		fakeBus.mem[0xD000+i] = 0xBB
+	}
+
+	regs := NewRegisters(nil, fakeBus, nil)
+	regs.ConnectOAM(oam)
+	dma := &regs.DMATransfer
+
+	regs.Write(0x46, 0xC0)
+	clockDMA(regs, dmaStartDelay+10)
+
+	// The old transfer keeps the bus while the new one sets up
+	regs.Write(0x46, 0xD0)
+	for range dmaStartDelay {
+		if v, _ := dma.Conflict(0x0000); v != 0xAA {
+			t.Fatalf("conflict during restart = %02X, want the old transfer's AA", v)
+		}
+		dma.MClock()
+	}
+	if v, _ := dma.Conflict(0x0000); v != 0xBB {
+		t.Errorf("conflict after restart = %02X, want the new transfer's BB", v)
+	}
+
+	clockDMA(regs, oamSize)
+	for i := range uint16(oamSize) {
+		if oam.mem[i] != 0xBB {
+			t.Fatalf("OAM[%d] = %02X, want BB from the restarted transfer", i, oam.mem[i])
+		}
+	}
+}
+
+func TestDMAEchoSource(t *testing.T) {
+	fakeBus := &FakeBus{mem: make(map[uint16]uint8)}
+	fakeBus.mem[0xDE00] = 0x77
+
+	regs := NewRegisters(nil, fakeBus, nil)
+	regs.Write(0x46, 0xFE)
+	clockDMA(regs, dmaStartDelay+1)
+
+	if got := fakeBus.mem[0xFE00]; got != 0x77 {
+		t.Errorf("DMA from FE00 copied %02X, want DE00's 77", got)
+	}
+}
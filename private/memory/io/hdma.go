@@ -0,0 +1,179 @@
+package io
+
+import (
+	"github.com/colecrouter/gameboy-go/private/memory"
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+// hdmaBlockSize is how many bytes VRAM DMA copies at a time, and the unit of its length.
+const hdmaBlockSize = 0x10
+
+// HDMA is the CGB's VRAM DMA, which copies from ROM or RAM to VRAM in blocks of 16 bytes.
+// A general-purpose transfer copies everything at once. An HBlank transfer copies one block each HBlank, and can be cancelled.
+// The CPU is stalled while a block is copied, which takes 8 M-cycles at normal speed and 16 at double speed.
+// https://gbdev.io/pandocs/CGB_Registers.html#lcd-vram-dma-transfers
+type HDMA struct {
+	bus         memory.Device
+	vram        memory.Device
+	broadcaster *system.Broadcaster
+	status      *LCDStatus
+	lcd         *LCDControl
+
+	Source uint16 // 0xFF51-0xFF52, low 4 bits ignored
+	Dest   uint16 // 0xFF53-0xFF54, offset into VRAM, low 4 bits ignored
+	Length uint8  // 0xFF55 bits 0-6, blocks left minus one
+	HBlank bool   // 0xFF55 bit 7, copy a block per HBlank
+	active bool
+
+	copying  uint16 // Bytes left in the block being copied
+	lastMode PPUState
+
+	clock    <-chan struct{}
+	clockAck chan<- struct{}
+}
+
+func NewHDMA(broadcaster *system.Broadcaster, bus memory.Device, lcd *LCDControl, status *LCDStatus) *HDMA {
+	d := &HDMA{bus: bus, broadcaster: broadcaster, lcd: lcd, status: status, Length: 0x7F}
+	if broadcaster != nil {
+		d.clock, d.clockAck = broadcaster.Subscribe(system.MFallingEdge)
+	}
+	return d
+}
+
+func (d *HDMA) Read(addr uint16) uint8 {
+	switch addr {
+	case 0, 1, 2, 3:
+		// Write-only
+		return 0xFF
+	case 4:
+		if d.active {
+			return d.Length
+		}
+		return 0x80 | d.Length
+	default:
+		panic("Invalid address")
+	}
+}
+
+func (d *HDMA) Write(addr uint16, value uint8) {
+	switch addr {
+	case 0:
+		d.Source = uint16(value)<<8 | d.Source&0x00FF
+	case 1:
+		d.Source = d.Source&0xFF00 | uint16(value&0xF0)
+	case 2:
+		d.Dest = uint16(value&0x1F)<<8 | d.Dest&0x00FF
+	case 3:
+		d.Dest = d.Dest&0xFF00 | uint16(value&0xF0)
+	case 4:
+		d.start(value)
+	default:
+		panic("Invalid address")
+	}
+}
+
+// start handles a write to 0xFF55, which starts a transfer, or cancels a running HBlank transfer if bit 7 is clear.
+func (d *HDMA) start(value uint8) {
+	if d.active && d.HBlank && value&0x80 == 0 {
+		d.active = false
+		return
+	}
+
+	d.Length = value & 0x7F
+	d.HBlank = value&0x80 != 0
+	d.active = true
+
+	// A general-purpose transfer starts right away, and so does an HBlank transfer started during HBlank
+	if !d.HBlank || d.inHBlank() {
+		d.copying = hdmaBlockSize
+	}
+	d.lastMode = d.mode()
+}
+
+// Active reports whether a transfer is in progress, including HBlank transfers waiting for the next HBlank.
+func (d *HDMA) Active() bool {
+	return d.active
+}
+
+// Busy reports whether a block is being copied, which stalls the CPU.
+func (d *HDMA) Busy() bool {
+	return d.copying > 0
+}
+
+// MClock advances the transfer by one M-cycle.
+func (d *HDMA) MClock() {
+	// HBlank transfers copy a block each time the PPU enters HBlank
+	mode := d.mode()
+	if d.active && d.HBlank && d.copying == 0 && mode != d.lastMode && d.inHBlank() {
+		d.copying = hdmaBlockSize
+	}
+	d.lastMode = mode
+
+	if d.copying == 0 {
+		return
+	}
+
+	// The DMA runs at the same rate at either speed, so it gets half as much done each M-cycle at double speed
+	n := 2
+	if d.broadcaster != nil && d.broadcaster.DoubleSpeed() {
+		n = 1
+	}
+	for range n {
+		offset := hdmaBlockSize - d.copying
+		d.write(d.Dest+offset, dmaRead(d.bus, d.Source+offset))
+		d.copying--
+		if d.copying == 0 {
+			d.nextBlock()
+			return
+		}
+	}
+}
+
+// nextBlock moves on after a block is copied, ending the transfer after the last one.
+func (d *HDMA) nextBlock() {
+	d.Source += hdmaBlockSize
+	d.Dest = (d.Dest + hdmaBlockSize) & 0x1FF0
+
+	if d.Length == 0 {
+		d.Length = 0x7F
+		d.active = false
+		return
+	}
+	d.Length--
+
+	if !d.HBlank {
+		d.copying = hdmaBlockSize
+	}
+}
+
+func (d *HDMA) mode() PPUState {
+	if d.status == nil {
+		return HBlank
+	}
+	return d.status.PPUMode
+}
+
+func (d *HDMA) inHBlank() bool {
+	return d.lcd != nil && d.lcd.EnableLCD && d.mode() == HBlank
+}
+
+func (d *HDMA) write(offset uint16, value uint8) {
+	offset &= 0x1FFF
+	if d.vram != nil {
+		d.vram.Write(offset, value)
+		return
+	}
+	d.bus.Write(0x8000+offset, value)
+}
+
+func (d *HDMA) Run(close <-chan struct{}) {
+	for {
+		select {
+		case <-close:
+			return
+		case <-d.clock:
+			d.MClock()
+			d.clockAck <- struct{}{}
+		}
+	}
+}
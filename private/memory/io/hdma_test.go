@@ -0,0 +1,157 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+// newHDMARegisters returns CGB registers on a bus with a pattern from 0xC000, and where VRAM DMA starts copying from there to 0x8000.
+func newHDMARegisters(b *system.Broadcaster) (*Registers, *FakeBus) {
+	fakeBus := &FakeBus{mem: make(map[uint16]uint8)}
+	for i := range 0x100 {
+		fakeBus.mem[0xC000+uint16(i)] = uint8(i) ^ 0xA5
+	}
+
+	r := NewRegisters(b, fakeBus, &Interrupt{})
+	r.CGBMode = true
+	r.Write(0x51, 0xC0)
+	r.Write(0x52, 0x0F) // Low 4 bits ignored
+	r.Write(0x53, 0xE0) // Only 0x8000-0x9FF0
+	r.Write(0x54, 0x00)
+	return r, fakeBus
+}
+
+// copied reports how many bytes from the start of the pattern have made it to VRAM.
+func copied(fakeBus *FakeBus) int {
+	for i := range 0x100 {
+		if v, ok := fakeBus.mem[0x8000+uint16(i)]; !ok || v != uint8(i)^0xA5 {
+			return i
+		}
+	}
+	return 0x100
+}
+
+func clockHDMA(r *Registers, n int) {
+	for range n {
+		r.VRAMDMA.MClock()
+	}
+}
+
+func TestGeneralPurposeDMA(t *testing.T) {
+	r, fakeBus := newHDMARegisters(nil)
+
+	// 3 blocks
+	r.Write(0x55, 0x02)
+	if !r.VRAMDMA.Busy() {
+		t.Fatal("transfer didn't start")
+	}
+	if got := r.Read(0x55); got != 0x02 {
+		t.Errorf("FF55 while running = %02X, want 02", got)
+	}
+
+	// 2 bytes per M-cycle, with the CPU stalled throughout
+	clockHDMA(r, 23)
+	if got := copied(fakeBus); got != 46 {
+		t.Errorf("after 23 M-cycles, %d bytes copied, want 46", got)
+	}
+	if !r.VRAMDMA.Busy() {
+		t.Error("CPU released before the transfer finished")
+	}
+
+	clockHDMA(r, 1)
+	if got := copied(fakeBus); got != 48 {
+		t.Errorf("%d bytes copied, want 48", got)
+	}
+	if r.VRAMDMA.Busy() || r.VRAMDMA.Active() {
+		t.Error("transfer still running")
+	}
+	if got := r.Read(0x55); got != 0xFF {
+		t.Errorf("FF55 when done = %02X, want FF", got)
+	}
+	if _, ok := fakeBus.mem[0x8030]; ok {
+		t.Error("copied past the end of the transfer")
+	}
+}
+
+func TestHBlankDMA(t *testing.T) {
+	r, fakeBus := newHDMARegisters(nil)
+	r.LCDControl.EnableLCD = true
+	r.LCDStatus.PPUMode = OAMScan
+
+	// 4 blocks, waiting for HBlank
+	r.Write(0x55, 0x83)
+	clockHDMA(r, 20)
+	if r.VRAMDMA.Busy() || copied(fakeBus) != 0 {
+		t.Fatal("copied before HBlank")
+	}
+	if got := r.Read(0x55); got != 0x03 {
+		t.Errorf("FF55 = %02X, want 03", got)
+	}
+
+	// One block per HBlank
+	for line := 1; line <= 2; line++ {
+		r.LCDStatus.PPUMode = HBlank
+		clockHDMA(r, 20)
+		if got := copied(fakeBus); got != line*hdmaBlockSize {
+			t.Errorf("after HBlank %d, %d bytes copied, want %d", line, got, line*hdmaBlockSize)
+		}
+		r.LCDStatus.PPUMode = OAMScan
+		clockHDMA(r, 1)
+	}
+	if got := r.Read(0x55); got != 0x01 {
+		t.Errorf("FF55 after 2 blocks = %02X, want 01", got)
+	}
+
+	// Cancelled with 2 blocks left
+	r.Write(0x55, 0x00)
+	if r.VRAMDMA.Active() {
+		t.Fatal("transfer not cancelled")
+	}
+	if got := r.Read(0x55); got != 0x81 {
+		t.Errorf("FF55 after cancel = %02X, want 81", got)
+	}
+	r.LCDStatus.PPUMode = HBlank
+	clockHDMA(r, 20)
+	if got := copied(fakeBus); got != 2*hdmaBlockSize {
+		t.Errorf("%d bytes copied after cancel, want %d", got, 2*hdmaBlockSize)
+	}
+
+	// No copying with the LCD off
+	r.LCDStatus.PPUMode = OAMScan
+	r.LCDControl.EnableLCD = false
+	r.Write(0x55, 0x80)
+	r.LCDStatus.PPUMode = HBlank
+	clockHDMA(r, 20)
+	if r.VRAMDMA.Busy() || !r.VRAMDMA.Active() {
+		t.Error("HBlank transfer ran with the LCD off")
+	}
+}
+
+func TestHDMADoubleSpeed(t *testing.T) {
+	var b system.Broadcaster
+	b.SetDoubleSpeed(true)
+	r, fakeBus := newHDMARegisters(&b)
+
+	r.Write(0x55, 0x00)
+	clockHDMA(r, hdmaBlockSize-1)
+	if got := copied(fakeBus); got != hdmaBlockSize-1 {
+		t.Errorf("%d bytes copied, want %d", got, hdmaBlockSize-1)
+	}
+	clockHDMA(r, 1)
+	if r.VRAMDMA.Busy() {
+		t.Error("block took more than 16 M-cycles")
+	}
+}
+
+func TestHDMALocked(t *testing.T) {
+	r, _ := newHDMARegisters(nil)
+	r.CGBMode = false
+	r.Write(0x55, 0x00)
+	if r.VRAMDMA.Active() {
+		t.Error("VRAM DMA started outside CGB mode")
+	}
+	if got := r.Read(0x55); got != 0xFF {
+		t.Errorf("FF55 = %02X, want FF", got)
+	}
+}
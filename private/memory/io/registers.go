@@ -8,10 +8,12 @@ import (
 type Registers struct {
 	initialized bool
 	bus         memory.Device
+	paletteLock func() bool
 
 	JoypadState    JoyPad         // 0xFF00
 	Serial         SerialTransfer // 0xFF01-0xFF02
 	Timer          Timer          // 0xFF04-0xFF07
+	DMATransfer    OAMDMA         // Started by writing 0xFF46
 	InterruptFlag  *Interrupt     // 0xFF0F
 	Audio          uint32         // 0xFF10-0xFF26
 	WavePattern    uint16         // 0xFF30-0xFF3F
@@ -29,12 +31,14 @@ type Registers struct {
 
 	// CGB only
 	// TODO
-	VRAMBank1      bool     // 0xFF4F
-	DisableBootROM bool     // 0xFF50
-	VRAMDMA        [5]uint8 // 0xFF51-0xFF55
-	WRAMBank       uint8    // 0xFF70
-	GBCPaletteData [8]uint8 // 0xFF68-0xFF6B
-	WRAMBank1      bool     // 0xFF70
+	CGBMode        bool          // 0xFF4C - Unlocks the CGB registers. A CGB boot ROM clears it for DMG games
+	Speed          SpeedSwitch   // 0xFF4D
+	VRAMBank1      bool          // 0xFF4F
+	DisableBootROM bool          // 0xFF50
+	VRAMDMA        HDMA          // 0xFF51-0xFF55
+	WRAMBank       uint8         // 0xFF70 - Bits 0-2
+	BGPalettes     ColorPalettes // 0xFF68-0xFF69
+	OBJPalettes    ColorPalettes // 0xFF6A-0xFF6B
 
 	// ???
 	rest [0x8A]uint8
@@ -48,6 +52,8 @@ $FF0F		DMG	Interrupts
 $FF10	$FF26	DMG	Audio
 $FF30	$FF3F	DMG	Wave pattern
 $FF40	$FF4B	DMG	LCD Control, Status, Position, Scrolling, and Palettes
+$FF4C		CGB	KEY0, CGB or DMG compatibility mode
+$FF4D		CGB	KEY1, Speed switch
 $FF4F		CGB	VRAM Bank Select
 $FF50		DMG	Set to non-zero to disable boot ROM
 $FF51	$FF55	CGB	VRAM DMA
@@ -56,14 +62,18 @@ $FF70		CGB	WRAM Bank Select
 */
 
 func NewRegisters(broadcaster *system.Broadcaster, bus memory.Device, ir *Interrupt) *Registers {
-	return &Registers{
+	r := &Registers{
 		bus:           bus,
 		initialized:   true,
 		Serial:        *NewSerialTransfer(ir),
 		Timer:         *NewTimer(broadcaster, ir),
+		DMATransfer:   *NewOAMDMA(broadcaster, bus),
+		Speed:         *NewSpeedSwitch(broadcaster),
 		JoypadState:   *NewJoyPad(ir),
 		InterruptFlag: ir,
 	}
+	r.VRAMDMA = *NewHDMA(broadcaster, bus, &r.LCDControl, &r.LCDStatus)
+	return r
 }
 
 func (r *Registers) Read(addr uint16) uint8 {
@@ -113,27 +123,39 @@ func (r *Registers) Read(addr uint16) uint8 {
 		return r.WindowY
 	case 0x4B:
 		return r.WindowX
+	case 0x4D:
+		if !r.CGBMode {
+			return 0xFF
+		}
+		return r.Speed.Read(0)
 	case 0x50:
 		if r.DisableBootROM {
 			return 1
 		}
 		return 0
 	case 0x4F:
+		if !r.CGBMode {
+			return 0xFF
+		}
 		if r.VRAMBank1 {
-			return 1
+			return 0xFF
 		}
-		return 0
+		return 0xFE
 	case 0x68, 0x69, 0x6A, 0x6B:
-		offset := addr - 0x68
-		return r.GBCPaletteData[offset]
+		if !r.CGBMode || (addr&1 == 1 && r.paletteBlocked()) {
+			return 0xFF
+		}
+		return r.colorPalettes(addr).Read(addr & 1)
 	case 0x51, 0x52, 0x53, 0x54, 0x55:
-		offset := addr - 0x51
-		return r.VRAMDMA[offset]
+		if !r.CGBMode {
+			return 0xFF
+		}
+		return r.VRAMDMA.Read(addr - 0x51)
 	case 0x70:
-		if r.WRAMBank1 {
-			return 1
+		if !r.CGBMode {
+			return 0xFF
 		}
-		return 0
+		return 0xF8 | r.WRAMBank
 	default:
 		return 0
 	}
@@ -175,7 +197,7 @@ func (r *Registers) Write(addr uint16, value uint8) {
 		r.LYCompare = value
 	case 0x46:
 		r.DMA = value
-		r.dma(value)
+		r.DMATransfer.Start(value)
 	case 0x47:
 		r.TilePalette.Write(0, value)
 	case 0x48:
@@ -186,18 +208,43 @@ func (r *Registers) Write(addr uint16, value uint8) {
 		r.WindowY = value
 	case 0x4B:
 		r.WindowX = value
+	case 0x4C:
+		// Only the boot ROM can drop to DMG compatibility mode
+		if !r.DisableBootROM && value&0x04 != 0 {
+			r.CGBMode = false
+		}
+	case 0x4D:
+		if r.CGBMode {
+			r.Speed.Write(0, value)
+		}
 	case 0x50:
-		r.DisableBootROM = value > 0
+		// Once unmapped, the boot ROM can't be mapped back in
+		if value > 0 {
+			r.DisableBootROM = true
+		}
 	case 0x4F:
-		r.VRAMBank1 = value > 0
+		if r.CGBMode {
+			r.VRAMBank1 = value&1 != 0
+		}
 	case 0x68, 0x69, 0x6A, 0x6B:
-		offset := addr - 0x68
-		r.GBCPaletteData[offset] = value
+		if !r.CGBMode {
+			return
+		}
+		p := r.colorPalettes(addr)
+		// Palette RAM is locked during pixel transfer, but the index still moves on
+		if addr&1 == 1 && r.paletteBlocked() {
+			p.increment()
+			return
+		}
+		p.Write(addr&1, value)
 	case 0x51, 0x52, 0x53, 0x54, 0x55:
-		offset := addr - 0x51
-		r.VRAMDMA[offset] = value
+		if r.CGBMode {
+			r.VRAMDMA.Write(addr-0x51, value)
+		}
 	case 0x70:
-		r.WRAMBank1 = value > 0
+		if r.CGBMode {
+			r.WRAMBank = value & 0x07
+		}
 	default:
 		// if addr >= 0x71 && addr <= 0xFF {
 		// 	r.rest[addr-0x71] = value
@@ -209,10 +256,34 @@ func (r *Registers) Write(addr uint16, value uint8) {
 	}
 }
 
-func (r *Registers) dma(addr uint8) {
-	source := uint16(addr) << 8 // Source address is (addr << 8)
-	for i := 0; i < 0xA0; i++ {
-		value := r.bus.Read(source + uint16(i))
-		r.bus.Write(0xFE00+uint16(i), value)
+// ConnectVRAM gives HDMA direct access to VRAM, like ConnectOAM does for OAM DMA. Writes land in the bank VBK selects.
+func (r *Registers) ConnectVRAM(vram memory.Device) {
+	r.VRAMDMA.vram = vram
+}
+
+// colorPalettes returns the palette RAM behind a CGB palette register.
+func (r *Registers) colorPalettes(addr uint16) *ColorPalettes {
+	if addr >= 0x6A {
+		return &r.OBJPalettes
+	}
+	return &r.BGPalettes
+}
+
+// ConnectPaletteLock sets what decides whether palette RAM is locked, so it can be turned off along with the VRAM lock.
+// Without it, palette RAM is locked during pixel transfer.
+func (r *Registers) ConnectPaletteLock(blocked func() bool) {
+	r.paletteLock = blocked
+}
+
+// paletteBlocked reports whether the PPU is reading palette RAM, during pixel transfer.
+func (r *Registers) paletteBlocked() bool {
+	if r.paletteLock != nil {
+		return r.paletteLock()
 	}
+	return r.LCDControl.EnableLCD && r.LCDStatus.PPUMode == Drawing
+}
+
+// ConnectOAM gives OAM DMA direct access to OAM, bypassing the PPU lockout; without it, DMA writes through the bus.
+func (r *Registers) ConnectOAM(oam memory.Device) {
+	r.DMATransfer.oam = oam
 }
@@ -0,0 +1,54 @@
+package io
+
+import "github.com/colecrouter/gameboy-go/private/system"
+
+// SpeedSwitch is KEY1, which switches a CGB between normal and double speed.
+// A switch is armed by writing bit 0, then happens on the next STOP.
+type SpeedSwitch struct {
+	broadcaster *system.Broadcaster
+
+	Double bool // Bit 7 - Current speed
+	Armed  bool // Bit 0 - Switch on the next STOP
+}
+
+func NewSpeedSwitch(broadcaster *system.Broadcaster) *SpeedSwitch {
+	return &SpeedSwitch{broadcaster: broadcaster}
+}
+
+func (s *SpeedSwitch) Read(addr uint16) uint8 {
+	val := uint8(0x7E)
+	if s.Double {
+		val |= 1 << 7
+	}
+	if s.Armed {
+		val |= 1 << 0
+	}
+	return val
+}
+
+func (s *SpeedSwitch) Write(addr uint16, value uint8) {
+	s.Armed = value&1 != 0
+}
+
+// Switch toggles the speed if a switch is armed, reporting whether it did.
+func (s *SpeedSwitch) Switch() bool {
+	if !s.Armed {
+		return false
+	}
+	s.Armed = false
+	s.set(!s.Double)
+	return true
+}
+
+// Reset goes back to normal speed.
+func (s *SpeedSwitch) Reset() {
+	s.Armed = false
+	s.set(false)
+}
+
+func (s *SpeedSwitch) set(double bool) {
+	s.Double = double
+	if s.broadcaster != nil {
+		s.broadcaster.SetDoubleSpeed(double)
+	}
+}
@@ -0,0 +1,102 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+func TestSpeedSwitch(t *testing.T) {
+	var b system.Broadcaster
+	r := NewRegisters(&b, nil, &Interrupt{})
+
+	// Locked outside CGB mode
+	r.Write(0x4D, 0x01)
+	if got := r.Read(0x4D); got != 0xFF {
+		t.Errorf("KEY1 in DMG mode = %02X, want FF", got)
+	}
+	if r.Speed.Switch() {
+		t.Fatal("switched without being armed")
+	}
+
+	r.CGBMode = true
+	if got := r.Read(0x4D); got != 0x7E {
+		t.Errorf("KEY1 = %02X, want 7E", got)
+	}
+	r.Write(0x4D, 0x01)
+	if got := r.Read(0x4D); got != 0x7F {
+		t.Errorf("KEY1 armed = %02X, want 7F", got)
+	}
+
+	if !r.Speed.Switch() {
+		t.Fatal("armed switch didn't happen")
+	}
+	if got := r.Read(0x4D); got != 0xFE {
+		t.Errorf("KEY1 double speed = %02X, want FE", got)
+	}
+	if !b.DoubleSpeed() {
+		t.Error("broadcaster not at double speed")
+	}
+
+	r.Speed.Reset()
+	if r.Speed.Double || b.DoubleSpeed() {
+		t.Error("reset didn't go back to normal speed")
+	}
+}
+
+func TestKEY0(t *testing.T) {
+	r := NewRegisters(nil, nil, &Interrupt{})
+	r.CGBMode = true
+
+	r.Write(0x4C, 0x80)
+	if !r.CGBMode {
+		t.Fatal("CGB game dropped out of CGB mode")
+	}
+
+	r.Write(0x4C, 0x04)
+	if r.CGBMode {
+		t.Fatal("DMG game left in CGB mode")
+	}
+
+	// Locked once the boot ROM is gone
+	r.CGBMode = true
+	r.Write(0x50, 0x01)
+	r.Write(0x4C, 0x04)
+	if !r.CGBMode {
+		t.Error("KEY0 writable after boot")
+	}
+}
+
+func TestDoubleSpeedClock(t *testing.T) {
+	var b system.Broadcaster
+	tick, ack := b.Subscribe(system.MRisingEdge)
+
+	count := func() int {
+		n := 0
+		done := make(chan struct{})
+		go func() {
+			for {
+				select {
+				case <-tick:
+					n++
+					ack <- struct{}{}
+				case <-done:
+					return
+				}
+			}
+		}()
+		for range 16 {
+			b.TClock()
+		}
+		close(done)
+		return n
+	}
+
+	if n := count(); n != 4 {
+		t.Errorf("normal speed: %d M-cycles in 16 T-cycles, want 4", n)
+	}
+	b.SetDoubleSpeed(true)
+	if n := count(); n != 8 {
+		t.Errorf("double speed: %d M-cycles in 16 T-cycles, want 8", n)
+	}
+}
@@ -0,0 +1,34 @@
+package memory
+
+import "github.com/colecrouter/gameboy-go/private/system"
+
+// Prohibited is the unusable area at 0xFEA0-0xFEFF. What it reads as depends on the model.
+// https://gbdev.io/pandocs/Memory_Map.html#fea0feff-range
+type Prohibited struct {
+	model      system.Model
+	oamBlocked func() bool
+}
+
+// NewProhibited creates the unusable area for a model. oamBlocked reports whether the PPU currently has OAM locked.
+func NewProhibited(model system.Model, oamBlocked func() bool) *Prohibited {
+	return &Prohibited{model: model, oamBlocked: oamBlocked}
+}
+
+func (p *Prohibited) Read(addr uint16) uint8 {
+	// While OAM is locked, this area reads as 0xFF.
+	// On DMG the read would also corrupt OAM, which isn't emulated.
+	if p.oamBlocked != nil && p.oamBlocked() {
+		return 0xFF
+	}
+
+	if p.model == system.MODEL_CGB {
+		// Later CGB revisions return the high nibble of the low address byte twice, e.g. 0xFEAx reads 0xAA
+		low := uint8(0xA0 + addr)
+		return low&0xF0 | low>>4
+	}
+	return 0x00
+}
+
+// Write is ignored, as nothing is there to store the value.
+func (p *Prohibited) Write(addr uint16, data uint8) {
+}
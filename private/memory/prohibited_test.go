@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+func TestProhibited(t *testing.T) {
+	blocked := false
+	isBlocked := func() bool { return blocked }
+
+	dmg := NewProhibited(system.MODEL_DMG, isBlocked)
+	cgb := NewProhibited(system.MODEL_CGB, isBlocked)
+
+	tests := []struct {
+		addr     uint16
+		dmg, cgb uint8
+	}{
+		{0x00, 0x00, 0xAA}, // 0xFEA0
+		{0x0F, 0x00, 0xAA}, // 0xFEAF
+		{0x10, 0x00, 0xBB}, // 0xFEB0
+		{0x5F, 0x00, 0xFF}, // 0xFEFF
+	}
+
+	for _, tt := range tests {
+		dmg.Write(tt.addr, 0x12)
+		if got := dmg.Read(tt.addr); got != tt.dmg {
+			t.Errorf("DMG Read(%02X) = %02X, want %02X", tt.addr, got, tt.dmg)
+		}
+		if got := cgb.Read(tt.addr); got != tt.cgb {
+			t.Errorf("CGB Read(%02X) = %02X, want %02X", tt.addr, got, tt.cgb)
+		}
+	}
+
+	blocked = true
+	if got := dmg.Read(0x00); got != 0xFF {
+		t.Errorf("read while OAM is blocked = %02X, want FF", got)
+	}
+}
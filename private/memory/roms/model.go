@@ -0,0 +1,90 @@
+package bootroms
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+// BootROM is a boot ROM image for a particular model.
+type BootROM struct {
+	Model system.Model
+	Data  []byte
+}
+
+// DMG is the embedded DMG boot ROM.
+var DMG = &BootROM{Model: system.MODEL_DMG, Data: DMG_BOOT[:]}
+
+// Sizes of the boot ROM images. The CGB boot ROM is 2304 bytes, but 0x100-0x1FF is never mapped,
+// since the cartridge header lives there.
+const (
+	DMG_BOOT_SIZE = 0x100
+	CGB_BOOT_SIZE = 0x900
+)
+
+// MD5 hashes of known good dumps, for each model.
+var knownHashes = map[string]system.Model{
+	"a8f84a0ac44da5d3f0ee19f9cea80a8c": system.MODEL_DMG0,
+	"32fbbd84168d3482956eb3c5051637f5": system.MODEL_DMG,
+	"71a378e71ff30b2d8a1f02bf5c7896aa": system.MODEL_MGB,
+	"d574d4f9c12f305074798f54c091a8b4": system.MODEL_SGB,
+	"e0430bca9925fb9882148fd2dc2418c1": system.MODEL_SGB, // SGB2
+	"dbfce9db9deaa2567f6a84fde55f9680": system.MODEL_CGB,
+}
+
+// Size returns the size of the boot ROM image for a model.
+func Size(m system.Model) int {
+	if m == system.MODEL_CGB {
+		return CGB_BOOT_SIZE
+	}
+	return DMG_BOOT_SIZE
+}
+
+// New validates a boot ROM image for a model, by its size and against the hashes of known dumps.
+func New(m system.Model, data []byte) (*BootROM, error) {
+	if len(data) != Size(m) {
+		return nil, fmt.Errorf("%s boot ROM must be %d bytes, got %d", m, Size(m), len(data))
+	}
+
+	sum := md5.Sum(data)
+	known, ok := knownHashes[hex.EncodeToString(sum[:])]
+	if !ok {
+		return nil, fmt.Errorf("%s boot ROM doesn't match any known dump (MD5 %x)", m, sum)
+	}
+	if known != m {
+		return nil, fmt.Errorf("boot ROM is for %s, not %s", known, m)
+	}
+
+	return &BootROM{Model: m, Data: data}, nil
+}
+
+// Identify validates a boot ROM image, working out its model from its hash.
+func Identify(data []byte) (*BootROM, error) {
+	sum := md5.Sum(data)
+	m, ok := knownHashes[hex.EncodeToString(sum[:])]
+	if !ok {
+		return nil, fmt.Errorf("boot ROM doesn't match any known dump (MD5 %x)", sum)
+	}
+	return New(m, data)
+}
+
+// LoadFile reads a boot ROM from disk and validates it for a model.
+func LoadFile(m system.Model, path string) (*BootROM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(m, data)
+}
+
+// Mapped reports whether addr reads from the boot ROM rather than the cartridge while the boot ROM is enabled.
+// https://gbdev.io/pandocs/Memory_Map.html
+func (b *BootROM) Mapped(addr uint16) bool {
+	if addr < 0x100 {
+		return true
+	}
+	return b.Model == system.MODEL_CGB && addr >= 0x200 && int(addr) < CGB_BOOT_SIZE
+}
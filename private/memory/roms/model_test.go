@@ -0,0 +1,76 @@
+package bootroms
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/system"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Known DMG dump", func(t *testing.T) {
+		b, err := New(system.MODEL_DMG, DMG_BOOT[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Model != system.MODEL_DMG {
+			t.Errorf("got model %s", b.Model)
+		}
+	})
+
+	t.Run("Identify", func(t *testing.T) {
+		b, err := Identify(DMG_BOOT[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.Model != system.MODEL_DMG {
+			t.Errorf("got model %s, want DMG", b.Model)
+		}
+	})
+
+	t.Run("Wrong model", func(t *testing.T) {
+		if _, err := New(system.MODEL_MGB, DMG_BOOT[:]); err == nil {
+			t.Error("accepted a DMG boot ROM as MGB")
+		}
+	})
+
+	t.Run("Wrong size", func(t *testing.T) {
+		if _, err := New(system.MODEL_CGB, DMG_BOOT[:]); err == nil {
+			t.Error("accepted a 256 byte CGB boot ROM")
+		}
+	})
+
+	t.Run("Unknown dump", func(t *testing.T) {
+		data := DMG_BOOT
+		data[0] ^= 0xFF
+		if _, err := New(system.MODEL_DMG, data[:]); err == nil {
+			t.Error("accepted a modified boot ROM")
+		}
+	})
+}
+
+func TestMapped(t *testing.T) {
+	dmg := &BootROM{Model: system.MODEL_DMG}
+	cgb := &BootROM{Model: system.MODEL_CGB}
+
+	tests := []struct {
+		addr     uint16
+		dmg, cgb bool
+	}{
+		{0x0000, true, true},
+		{0x00FF, true, true},
+		{0x0100, false, false}, // Cartridge header
+		{0x01FF, false, false},
+		{0x0200, false, true},
+		{0x08FF, false, true},
+		{0x0900, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := dmg.Mapped(tt.addr); got != tt.dmg {
+			t.Errorf("DMG Mapped(%04X) = %t, want %t", tt.addr, got, tt.dmg)
+		}
+		if got := cgb.Mapped(tt.addr); got != tt.cgb {
+			t.Errorf("CGB Mapped(%04X) = %t, want %t", tt.addr, got, tt.cgb)
+		}
+	}
+}
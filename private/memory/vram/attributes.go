@@ -0,0 +1,20 @@
+package vram
+
+// TileAttributes is a CGB BG map attribute, stored in bank 1 at the same place as the tile number in bank 0.
+type TileAttributes struct {
+	Priority bool  // Bit 7 - Drawn over sprites, except for color 0
+	FlipY    bool  // Bit 6
+	FlipX    bool  // Bit 5
+	UseBank1 bool  // Bit 3 - Tile data comes from bank 1
+	Palette  uint8 // Bits 0-2 - BG palette number
+}
+
+func NewTileAttributes(data uint8) TileAttributes {
+	return TileAttributes{
+		Priority: data&(1<<7) != 0,
+		FlipY:    data&(1<<6) != 0,
+		FlipX:    data&(1<<5) != 0,
+		UseBank1: data&(1<<3) != 0,
+		Palette:  data & 0b0000_0111,
+	}
+}
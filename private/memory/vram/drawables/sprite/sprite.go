@@ -2,6 +2,7 @@ package sprite
 
 import (
 	"github.com/colecrouter/gameboy-go/private/memory/vram"
+	"github.com/colecrouter/gameboy-go/private/memory/vram/drawables/tile"
 )
 
 type Sprite struct {
@@ -87,11 +88,20 @@ func NewSprite(vram *vram.VRAM, data [4]byte, enable8x16 *bool) *Sprite {
 	}
 }
 
+// Pixels returns a copy of the sprite's color indexes, from bank 1 if UseBank1 is set.
 func (s *Sprite) Pixels() []uint8 {
 	if *s.enable8x16 {
-		return append(s.vram.GetTile(int(s.tile)&0xFE).Pixels(), s.vram.GetTile(int(s.tile)|0x01).Pixels()...)
+		return append(s.tilePixels(int(s.tile)&0xFE), s.tilePixels(int(s.tile)|0x01)...)
 	}
-	return s.vram.GetTile(int(s.tile)).Pixels()
+	return s.tilePixels(int(s.tile))
+}
+
+func (s *Sprite) tilePixels(index int) []uint8 {
+	pixels := make([]uint8, tile.TILE_SIZE*tile.TILE_SIZE)
+	if t := s.vram.GetBankTile(s.UseBank1, index); t != nil {
+		copy(pixels, t.Pixels())
+	}
+	return pixels
 }
 
 // Y returns the Y coordinate of the sprite on the screen.
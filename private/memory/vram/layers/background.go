@@ -14,6 +14,7 @@ type BGLayer struct {
 	vram        *vram.VRAM
 	registers   *io.Registers
 	visibleSize image.Rectangle
+	priority    []bool
 }
 
 // NewBGLayer creates a new BGLayer.
@@ -30,6 +31,12 @@ func (bg *BGLayer) Image() image.Image {
 	img := image.NewPaletted(bg.visibleSize, monochrome.Palette)
 	tSize := tile.TILE_SIZE
 
+	// In CGB mode, LCDC bit 0 turns on the priority attribute instead of the background
+	bg.priority = nil
+	if bg.registers.CGBMode && bg.registers.LCDControl.EnableBackgroundAndWindow {
+		bg.priority = make([]bool, len(img.Pix))
+	}
+
 	// Determine addressing mode and tile map mode from registers.
 	addressingMode := vram.Mode8000
 	if !bg.registers.LCDControl.Use8000Method {
@@ -55,8 +62,8 @@ func (bg *BGLayer) Image() image.Image {
 			tileX := (startTileX + col) % 32
 			tileY := (startTileY + row) % 32
 
-			curTile := bg.vram.GetMappedTile(uint8(tileY), uint8(tileX), bgMapMode, addressingMode)
-			if curTile == nil {
+			raw, attrs := mapTile(bg.vram, bg.registers, uint8(tileY), uint8(tileX), bgMapMode, addressingMode)
+			if raw == nil {
 				continue
 			}
 
@@ -65,11 +72,11 @@ func (bg *BGLayer) Image() image.Image {
 			destX := col*tSize - (scrollX % tSize)
 			destY := row*tSize - (scrollY % tSize)
 
-			pixels := curTile.Pixels() // Convert the array to a slice.
-
 			// Map pixel values through the background palette.
-			for i := range pixels {
-				pixels[i] = bg.registers.TilePalette.Match(pixels[i])
+			pixels := shadeTile(bg.registers, attrs, raw)
+
+			if bg.priority != nil && attrs.Priority {
+				drawPriority(bg.priority, bg.visibleSize, raw, destY, destX)
 			}
 
 			// Draw the tile using our generic opaque blit.
@@ -79,3 +86,8 @@ func (bg *BGLayer) Image() image.Image {
 
 	return img
 }
+
+// Priority reports, for each pixel of the last image, whether it's drawn over sprites. It's nil outside of CGB mode.
+func (bg *BGLayer) Priority() []bool {
+	return bg.priority
+}
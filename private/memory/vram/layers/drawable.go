@@ -3,13 +3,68 @@ package layers
 import (
 	"image"
 
+	"github.com/colecrouter/gameboy-go/private/memory/io"
+	"github.com/colecrouter/gameboy-go/private/memory/vram"
 	"github.com/colecrouter/gameboy-go/private/memory/vram/drawables/tile"
 )
 
+// In CGB mode, layers draw palette entries rather than DMG shades.
+// Color c of BG palette p is p*COLORS_PER_PALETTE+c, and the sprite palettes follow from OBJ_PALETTE_BASE.
+const (
+	COLORS_PER_PALETTE = 4
+	OBJ_PALETTE_BASE   = 8 * COLORS_PER_PALETTE
+	CGB_PALETTE_SIZE   = 2 * OBJ_PALETTE_BASE
+)
+
 type Layer interface {
 	Image() image.Image
 }
 
+// mapTile looks up the tile at a map position, returning its color indexes and its CGB attributes.
+// The attributes are only used in CGB mode, where they pick the bank and flip the tile. It returns nil for tiles that haven't been written.
+func mapTile(v *vram.VRAM, regs *io.Registers, tileY, tileX uint8, mapMode vram.TileMapMode, addressingMode vram.TileAddressingMode) ([]uint8, vram.TileAttributes) {
+	var attrs vram.TileAttributes
+	if regs.CGBMode {
+		attrs = v.GetTileAttributes(tileY, tileX, mapMode)
+	}
+
+	t := v.GetMappedBankTile(tileY, tileX, mapMode, addressingMode, attrs.UseBank1)
+	if t == nil {
+		return nil, attrs
+	}
+	return transformPixels(t.Pixels(), tile.TILE_SIZE, tile.TILE_SIZE, attrs.FlipX, attrs.FlipY), attrs
+}
+
+// shadeTile maps BG or window color indexes to what's drawn: a DMG shade through BGP, or a CGB palette entry.
+func shadeTile(regs *io.Registers, attrs vram.TileAttributes, raw []uint8) []uint8 {
+	pixels := make([]uint8, len(raw))
+	for i, c := range raw {
+		if regs.CGBMode {
+			pixels[i] = attrs.Palette*COLORS_PER_PALETTE + c
+		} else {
+			pixels[i] = regs.TilePalette.Match(c)
+		}
+	}
+	return pixels
+}
+
+// drawPriority marks the pixels of a tile that are drawn over sprites, which is all but color 0.
+func drawPriority(dst []bool, bounds image.Rectangle, raw []uint8, startY, startX int) {
+	for y := 0; y < tile.TILE_SIZE; y++ {
+		destY := startY + y
+		if destY < bounds.Min.Y || destY >= bounds.Max.Y {
+			continue
+		}
+		for x := 0; x < tile.TILE_SIZE; x++ {
+			destX := startX + x
+			if destX < bounds.Min.X || destX >= bounds.Max.X {
+				continue
+			}
+			dst[(destY-bounds.Min.Y)*bounds.Dx()+destX-bounds.Min.X] = raw[y*tile.TILE_SIZE+x] != 0
+		}
+	}
+}
+
 func drawTile(dst *image.Paletted, pixels []uint8, startY, startX int) {
 	bounds := dst.Bounds()
 
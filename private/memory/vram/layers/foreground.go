@@ -14,6 +14,7 @@ type WindowLayer struct {
 	vram        *vram.VRAM
 	registers   *io.Registers
 	visibleSize image.Rectangle
+	priority    []bool
 }
 
 // NewWindowLayer creates a new WindowLayer.
@@ -30,6 +31,12 @@ func (w *WindowLayer) Image() image.Image {
 	img := image.NewPaletted(w.visibleSize, monochrome.Palette)
 	tSize := tile.TILE_SIZE
 
+	// In CGB mode, LCDC bit 0 turns on the priority attribute instead of the window
+	w.priority = nil
+	if w.registers.CGBMode && w.registers.LCDControl.EnableBackgroundAndWindow {
+		w.priority = make([]bool, len(img.Pix))
+	}
+
 	// Determine addressing mode, similar to the BG layer.
 	addressingMode := vram.Mode8000
 	if !w.registers.LCDControl.Use8000Method {
@@ -51,8 +58,8 @@ func (w *WindowLayer) Image() image.Image {
 	for row := 0; row < rows; row++ {
 		for col := 0; col < cols; col++ {
 			// The window tilemap is addressed directly (row & col are relative to the window).
-			curTile := w.vram.GetMappedTile(uint8(row), uint8(col), winMapMode, addressingMode)
-			if curTile == nil {
+			raw, attrs := mapTile(w.vram, w.registers, uint8(row), uint8(col), winMapMode, addressingMode)
+			if raw == nil {
 				continue
 			}
 
@@ -65,11 +72,11 @@ func (w *WindowLayer) Image() image.Image {
 				continue
 			}
 
-			pixels := curTile.Pixels()
-
 			// Map pixel values through the background palette.
-			for i := range pixels {
-				pixels[i] = w.registers.TilePalette.Match(pixels[i])
+			pixels := shadeTile(w.registers, attrs, raw)
+
+			if w.priority != nil && attrs.Priority {
+				drawPriority(w.priority, w.visibleSize, raw, destY, destX)
 			}
 
 			// Draw the tile using our generic opaque blit.
@@ -79,3 +86,17 @@ func (w *WindowLayer) Image() image.Image {
 
 	return img
 }
+
+// Bounds returns the part of the screen the window covers. It hides the background there, color 0 and all.
+func (w *WindowLayer) Bounds() image.Rectangle {
+	r := image.Rectangle{
+		Min: image.Pt(int(w.registers.WindowX)-7, int(w.registers.WindowY)),
+		Max: w.visibleSize.Max,
+	}
+	return r.Intersect(w.visibleSize)
+}
+
+// Priority reports, for each pixel of the last image, whether it's drawn over sprites. It's nil outside of CGB mode.
+func (w *WindowLayer) Priority() []bool {
+	return w.priority
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/colecrouter/gameboy-go/private/memory/vram/drawables/tile"
 )
 
-// transformPixels applies horizontal and/or vertical flip to a flat pixel array.
+// transformPixels applies horizontal and/or vertical flip to a flat pixel array, returning a copy.
 // `pixels` should be in row-major order with dimensions (width x height).
 func transformPixels(pixels []uint8, width, height int, flipX, flipY bool) []uint8 {
 	transformed := make([]uint8, len(pixels))
@@ -74,6 +74,10 @@ func (s *SpriteLayer) Image() image.Image {
 			continue
 		}
 
+		// Bank 1 only exists in CGB mode
+		if !s.registers.CGBMode {
+			spr.UseBank1 = false
+		}
 		pixels := spr.Pixels()
 
 		// Apply sprite flip transformations if needed.
@@ -82,9 +86,18 @@ func (s *SpriteLayer) Image() image.Image {
 		}
 
 		// Map pixel values through the sprite palette.
-		palette := s.registers.ObjectPalletes[spr.DMGPalette]
-		for i := range pixels {
-			pixels[i] = palette.Match(pixels[i])
+		if s.registers.CGBMode {
+			base := OBJ_PALETTE_BASE + spr.CGBPalette*COLORS_PER_PALETTE
+			for i, c := range pixels {
+				if c != 0 {
+					pixels[i] = base + c
+				}
+			}
+		} else {
+			palette := s.registers.ObjectPalletes[spr.DMGPalette]
+			for i := range pixels {
+				pixels[i] = palette.Match(pixels[i])
+			}
 		}
 
 		// Draw the sprite onto the frame.
@@ -11,39 +11,82 @@ type VRAM struct {
 	tileMap1 TileMap  // 0x9C00-0x9FFF
 
 	tiles [384]*tile.Tile
+
+	// CGB bank 1 has a second set of tiles, and the attributes of each tile in the maps
+	tileData1 TileData // 0x8000-0x97FF
+	attrMap0  TileMap  // 0x9800-0x9BFF
+	attrMap1  TileMap  // 0x9C00-0x9FFF
+
+	tiles1 [384]*tile.Tile
+
+	bank1 *bool // VBK, which bank the CPU sees
+}
+
+// ConnectBankSelect sets the flag that picks the bank seen through Read and Write, from VBK.
+// Without it, only bank 0 is accessible.
+func (v *VRAM) ConnectBankSelect(bank1 *bool) {
+	v.bank1 = bank1
 }
 
 func (v *VRAM) Read(addr uint16) uint8 {
+	if v.bank1 != nil && *v.bank1 {
+		return v.ReadBank(1, addr)
+	}
+	return v.ReadBank(0, addr)
+}
+
+func (v *VRAM) Write(addr uint16, data uint8) {
+	if v.bank1 != nil && *v.bank1 {
+		v.WriteBank(1, addr, data)
+		return
+	}
+	v.WriteBank(0, addr, data)
+}
+
+// ReadBank reads from a specific bank, regardless of VBK.
+func (v *VRAM) ReadBank(bank int, addr uint16) uint8 {
+	data, map0, map1, _ := v.bank(bank)
+
 	if addr < 0x1800 {
-		return v.tileData[addr]
+		return data[addr]
 	} else if addr < 0x1C00 {
-		return v.tileMap0[addr-0x1800]
+		return map0[addr-0x1800]
 	} else if addr < 0x2000 {
-		return v.tileMap1[addr-0x1C00]
+		return map1[addr-0x1C00]
 	} else {
 		panic("Invalid address")
 	}
 }
 
-func (v *VRAM) Write(addr uint16, data uint8) {
+// WriteBank writes to a specific bank, regardless of VBK.
+func (v *VRAM) WriteBank(bank int, addr uint16, value uint8) {
+	data, map0, map1, tiles := v.bank(bank)
+
 	if addr < 0x1800 {
-		v.tileData[addr] = data
+		data[addr] = value
 
 		// Figure out which tile this is and update it
 		index := addr / 16
 
 		var tileBytes [16]uint8
-		copy(tileBytes[:], v.tileData[index*16:index*16+16])
-		v.tiles[index] = tile.NewTile(tileBytes)
+		copy(tileBytes[:], data[index*16:index*16+16])
+		tiles[index] = tile.NewTile(tileBytes)
 	} else if addr < 0x1C00 {
-		v.tileMap0[addr-0x1800] = data
+		map0[addr-0x1800] = value
 	} else if addr < 0x2000 {
-		v.tileMap1[addr-0x1C00] = data
+		map1[addr-0x1C00] = value
 	} else {
 		panic("Invalid address")
 	}
 }
 
+func (v *VRAM) bank(bank int) (*TileData, *TileMap, *TileMap, *[384]*tile.Tile) {
+	if bank == 1 {
+		return &v.tileData1, &v.attrMap0, &v.attrMap1, &v.tiles1
+	}
+	return &v.tileData, &v.tileMap0, &v.tileMap1, &v.tiles
+}
+
 /*
 	Addressing modes:
 
@@ -76,6 +119,11 @@ const (
 
 // GetMappedTile reads a tile from the VRAM at the given tile coordinates (pixel coordinates divided by TILE_SIZE).
 func (v *VRAM) GetMappedTile(tileY, tileX uint8, mapMode TileMapMode, addressingMode TileAddressingMode) *tile.Tile {
+	return v.GetMappedBankTile(tileY, tileX, mapMode, addressingMode, false)
+}
+
+// GetMappedBankTile is GetMappedTile for CGB maps, where the attributes can pick a tile from bank 1.
+func (v *VRAM) GetMappedBankTile(tileY, tileX uint8, mapMode TileMapMode, addressingMode TileAddressingMode, bank1 bool) *tile.Tile {
 	mapIndex := uint16(tileY)*32 + uint16(tileX) // assuming a 32-tile wide tilemap; adjust if needed
 
 	currentMap := &v.tileMap0
@@ -95,7 +143,19 @@ func (v *VRAM) GetMappedTile(tileY, tileX uint8, mapMode TileMapMode, addressing
 		panic("Invalid addressing mode")
 	}
 
-	return v.GetTile(effectiveIndex)
+	return v.GetBankTile(bank1, effectiveIndex)
+}
+
+// GetTileAttributes reads the CGB attributes of the tile at the given tile coordinates.
+func (v *VRAM) GetTileAttributes(tileY, tileX uint8, mapMode TileMapMode) TileAttributes {
+	mapIndex := uint16(tileY)*32 + uint16(tileX)
+
+	currentMap := &v.attrMap0
+	if mapMode {
+		currentMap = &v.attrMap1
+	}
+
+	return NewTileAttributes(currentMap[mapIndex])
 }
 
 // GetTile reads a tile from the VRAM at the given index.
@@ -103,6 +163,14 @@ func (v *VRAM) GetTile(index int) *tile.Tile {
 	return v.tiles[index]
 }
 
+// GetBankTile reads a tile from either bank at the given index.
+func (v *VRAM) GetBankTile(bank1 bool, index int) *tile.Tile {
+	if bank1 {
+		return v.tiles1[index]
+	}
+	return v.tiles[index]
+}
+
 // GetTileMapValue returns the tile number from the selected tilemap.
 func (v *VRAM) GetTileMapValue(mapMode TileMapMode, index int) uint8 {
 	if mapMode == Map0 {
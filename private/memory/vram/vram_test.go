@@ -120,3 +120,51 @@ func TestTileRenderingModes(t *testing.T) {
 		}
 	})
 }
+
+func TestBanks(t *testing.T) {
+	v := &VRAM{}
+	var bank1 bool
+	v.ConnectBankSelect(&bank1)
+
+	// Same tile, different data in each bank
+	for i, b := range lines {
+		v.Write(uint16(i), b)
+	}
+	bank1 = true
+	v.Write(0x0000, 0xFF)
+	if got := v.Read(0x0000); got != 0xFF {
+		t.Errorf("bank 1 read = %02X, want FF", got)
+	}
+
+	// Bank 1's map area holds attributes: bank 1, flipped X, palette 5
+	v.Write(0x1800, 0b0010_1101)
+	bank1 = false
+	if got := v.Read(0x0000); got != lines[0] {
+		t.Errorf("bank 0 read = %02X, want %02X", got, lines[0])
+	}
+	if got := v.Read(0x1800); got != 0 {
+		t.Errorf("bank 0 map entry = %02X, want 00", got)
+	}
+
+	attrs := v.GetTileAttributes(0, 0, Map0)
+	want := TileAttributes{FlipX: true, UseBank1: true, Palette: 5}
+	if attrs != want {
+		t.Fatalf("attributes = %+v, want %+v", attrs, want)
+	}
+
+	if v.GetMappedBankTile(0, 0, Map0, Mode8000, false) != v.GetTile(0) {
+		t.Error("bank 0 tile not mapped")
+	}
+	ti := v.GetMappedBankTile(0, 0, Map0, Mode8000, true)
+	if ti == nil || ti == v.GetTile(0) {
+		t.Fatal("bank 1 tile not mapped")
+	}
+	// Only the first row's low bits were set in bank 1
+	if got := ti.Pixels()[:8]; !reflect.DeepEqual(got, []uint8{1, 1, 1, 1, 1, 1, 1, 1}) {
+		t.Errorf("bank 1 tile row 0 = %v", got)
+	}
+
+	if got := v.ReadBank(1, 0x1800); got != 0b0010_1101 {
+		t.Errorf("ReadBank(1) = %02X", got)
+	}
+}
@@ -0,0 +1,51 @@
+package memory
+
+const (
+	WRAM_BANK_SIZE = 0x1000
+	WRAM_BANKS     = 8
+)
+
+// WRAM is work RAM, mapped over 0xC000-0xDFFF. Bank 0 is always at 0xC000, and 0xD000 shows the bank picked by SVBK.
+// Only the CGB can switch banks. Selecting bank 0 gives bank 1, which is all a DMG has.
+type WRAM struct {
+	banks    [WRAM_BANKS][WRAM_BANK_SIZE]byte
+	selected *uint8
+}
+
+// NewWRAM creates work RAM that switches banks on selected, the SVBK register. Nil always uses bank 1.
+func NewWRAM(selected *uint8) *WRAM {
+	return &WRAM{selected: selected}
+}
+
+func (w *WRAM) Read(addr uint16) uint8 {
+	if addr < WRAM_BANK_SIZE {
+		return w.banks[0][addr]
+	}
+	return w.banks[w.Bank()][addr-WRAM_BANK_SIZE]
+}
+
+func (w *WRAM) Write(addr uint16, data uint8) {
+	if addr < WRAM_BANK_SIZE {
+		w.banks[0][addr] = data
+		return
+	}
+	w.banks[w.Bank()][addr-WRAM_BANK_SIZE] = data
+}
+
+// Bank returns the bank mapped at 0xD000.
+func (w *WRAM) Bank() int {
+	if w.selected == nil {
+		return 1
+	}
+	return max(int(*w.selected&0x07), 1)
+}
+
+// ReadBank reads from a specific bank, regardless of SVBK.
+func (w *WRAM) ReadBank(bank int, addr uint16) uint8 {
+	return w.banks[bank][addr]
+}
+
+// WriteBank writes to a specific bank, regardless of SVBK.
+func (w *WRAM) WriteBank(bank int, addr uint16, data uint8) {
+	w.banks[bank][addr] = data
+}
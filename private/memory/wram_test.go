@@ -0,0 +1,51 @@
+package memory
+
+import "testing"
+
+func TestWRAMBanks(t *testing.T) {
+	var svbk uint8
+	w := NewWRAM(&svbk)
+
+	b := &Bus{}
+	b.AddDevice(0xC000, 0xDFFF, w)
+	b.AddMirror(0xE000, 0xFDFF, 0xC000)
+
+	// Bank 0 selects bank 1
+	b.Write(0xC000, 0x10)
+	b.Write(0xD000, 0x11)
+	for bank := uint8(2); bank < WRAM_BANKS; bank++ {
+		svbk = bank
+		b.Write(0xD000, 0x10+bank)
+	}
+
+	svbk = 1
+	if got := b.Read(0xD000); got != 0x11 {
+		t.Errorf("bank 1 = %02X, want 11", got)
+	}
+
+	for bank := uint8(2); bank < WRAM_BANKS; bank++ {
+		svbk = bank
+		if got := b.Read(0xD000); got != 0x10+bank {
+			t.Errorf("bank %d = %02X, want %02X", bank, got, 0x10+bank)
+		}
+		// Bank 0 doesn't switch
+		if got := b.Read(0xC000); got != 0x10 {
+			t.Errorf("bank 0 with bank %d selected = %02X, want 10", bank, got)
+		}
+		// Echo RAM follows the selected bank
+		if got := b.Read(0xF000); got != 0x10+bank {
+			t.Errorf("echo of bank %d = %02X, want %02X", bank, got, 0x10+bank)
+		}
+	}
+
+	// Only the low 3 bits select
+	svbk = 0xF8
+	if got := w.Bank(); got != 1 {
+		t.Errorf("SVBK F8 selects bank %d, want 1", got)
+	}
+
+	b.Write(0xF000, 0x99)
+	if got := w.ReadBank(1, 0x000); got != 0x99 {
+		t.Errorf("echo write = %02X, want 99", got)
+	}
+}
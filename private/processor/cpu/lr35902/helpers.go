@@ -61,9 +61,14 @@ func (c *LR35902) Halt() {
 	c.halted = true
 }
 
-// Stop halts the CPU until a button is pressed
+// Stop halts the CPU until a button is pressed.
+// In CGB mode with a speed switch armed, it switches speed instead, pausing while the clock settles.
 func (c *LR35902) Stop() {
-	panic("not implemented")
+	c.io.Timer.Write(0, 0) // STOP resets DIV
+	c.stopped = true
+	if c.io.CGBMode && c.io.Speed.Switch() {
+		c.speedPause = SPEED_SWITCH_CYCLES
+	}
 }
 
 // EI enables interrupts
@@ -96,3 +101,9 @@ func (c *LR35902) Flags() *flags.Flags {
 func (c *LR35902) Registers() *registers.Registers {
 	return &c.registers
 }
+
+// LastPC returns the address of the instruction being executed, or the last one executed.
+// Unlike PC, it doesn't move past the opcode and operands as they're read.
+func (c *LR35902) LastPC() uint16 {
+	return c.lastPC
+}
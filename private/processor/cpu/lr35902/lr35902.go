@@ -12,6 +12,9 @@ import (
 	"github.com/colecrouter/gameboy-go/private/system"
 )
 
+// SPEED_SWITCH_CYCLES is how many M-cycles the CPU is paused for after a CGB speed switch.
+const SPEED_SWITCH_CYCLES = 2050
+
 // LR35902 is the original GameBoy CPU
 type LR35902 struct {
 	initialized bool
@@ -25,6 +28,8 @@ type LR35902 struct {
 	eiDelay     int
 	lastPC      uint16
 	halted      bool
+	stopped     bool
+	speedPause  int
 	clock       <-chan struct{}
 	clockAck    chan<- struct{}
 
@@ -37,6 +42,24 @@ func (c *LR35902) MClock() {
 		panic("CPU not initialized")
 	}
 
+	// VRAM DMA stalls the CPU while it copies
+	if c.io.VRAMDMA.Busy() {
+		c.ClockAndAck()
+		return
+	}
+
+	// STOP mode waits for a button press, or for the clock to settle after a speed switch
+	if c.stopped {
+		c.ClockAndAck()
+		if c.speedPause > 0 {
+			c.speedPause--
+			c.stopped = c.speedPause > 0
+		} else if c.io.JoypadState.Read(0)&0x0F != 0x0F {
+			c.stopped = false
+		}
+		return
+	}
+
 	ienable := c.ie.Read(0)
 	iflag := c.io.InterruptFlag.Read(0)
 
@@ -74,8 +97,14 @@ func (c *LR35902) MClock() {
 	}
 
 	// Fetch the next instruction
+	// Note where the instruction starts before fetching, so hooks see it for every access it makes.
+	// The second byte of a CB instruction belongs to the prefix's instruction.
+	if !c.cb {
+		c.lastPC = c.registers.PC
+	}
+
 	// We don't clock here, because the fetch stage overlaps with the previous instruction's execute stage
-	opcode = c.bus.Read(c.registers.PC)
+	opcode = c.bus.Fetch(c.registers.PC)
 
 	if c.cb {
 		instruction = instructions.CBInstructions[opcode]
@@ -88,14 +117,6 @@ func (c *LR35902) MClock() {
 
 	_ = mnemonic
 
-	immediate8 := c.bus.Read(c.registers.PC + 1)
-	immediate16 := helpers.ToRegisterPair(c.bus.Read(c.registers.PC+2), c.bus.Read(c.registers.PC+1))
-
-	_ = immediate8
-	_ = immediate16
-
-	c.lastPC = c.registers.PC
-
 	// if c.registers.PC == 0x29d0 {
 	// 	if c.registers.A&0b1111 != 0b1111 {
 	// 		fmt.Printf("")
@@ -162,8 +183,8 @@ func (c *LR35902) printStack() []uint16 {
 		if offset > 0xFFFE {
 			break
 		}
-		low := c.bus.Read(uint16(offset))
-		high := c.bus.Read(uint16(offset + 1))
+		low := c.bus.Peek(uint16(offset))
+		high := c.bus.Peek(uint16(offset + 1))
 		stack[j] = helpers.ToRegisterPair(high, low)
 	}
 
@@ -3,6 +3,7 @@ package lr35902
 import (
 	"testing"
 
+	"github.com/colecrouter/gameboy-go/private/memory"
 	"github.com/colecrouter/gameboy-go/private/processor/helpers"
 )
 
@@ -166,3 +167,50 @@ func TestJPHL(t *testing.T) {
 		t.Errorf("JP (HL) failed: got PC %d, want %d", c.registers.PC, jumpTarget)
 	}
 }
+
+func TestHookPC(t *testing.T) {
+	c, mem, bus := newTestCPU()
+	bus.SetTracer(c.LastPC, nil)
+
+	// LD A,(HL) at 0x0150, then BIT 0,(HL) at 0x0151
+	c.registers.PC = 0x0150
+	copy(mem.Buffer[0x0150:], []uint8{0x7E, 0xCB, 0x46})
+	c.registers.H, c.registers.L = helpers.FromRegisterPair(0x2345)
+
+	var got []memory.Access
+	bus.AddHook(memory.Hook{
+		Types: memory.ACCESS_READ | memory.ACCESS_EXECUTE,
+		Start: 0x0000,
+		End:   0xFFFF,
+		Func:  func(a memory.Access) { got = append(got, a) },
+	})
+
+	manualClock := make(chan struct{}, 16)
+	for range cap(manualClock) {
+		manualClock <- struct{}{}
+	}
+	c.clock = manualClock
+	c.clockAck = make(chan struct{}, 16)
+
+	// LD A,(HL), CB prefix, BIT 0,(HL)
+	for range 3 {
+		c.MClock()
+	}
+
+	// The reads of (HL) happen after PC has moved on, but belong to the instruction that made them
+	if len(got) < 5 {
+		t.Fatalf("got %d accesses, want at least 5: %+v", len(got), got)
+	}
+	if got[1].Type != memory.ACCESS_READ || got[1].Addr != 0x2345 {
+		t.Fatalf("second access = %+v, want the read of (HL)", got[1])
+	}
+	for i, a := range got {
+		want := uint16(0x0151)
+		if i < 2 {
+			want = 0x0150
+		}
+		if a.PC != want {
+			t.Errorf("access %d, type %d to %04X: PC %04X, want %04X", i, a.Type, a.Addr, a.PC, want)
+		}
+	}
+}
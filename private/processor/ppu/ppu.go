@@ -2,7 +2,10 @@ package ppu
 
 import (
 	"image"
+	"image/color"
+	"sync/atomic"
 
+	"github.com/colecrouter/gameboy-go/private/display/cgb"
 	"github.com/colecrouter/gameboy-go/private/display/monochrome"
 	"github.com/colecrouter/gameboy-go/private/memory"
 	"github.com/colecrouter/gameboy-go/private/memory/io"
@@ -20,6 +23,8 @@ type PPU struct {
 	image            *image.Paletted
 	clock            <-chan struct{}
 	clockAck         chan<- struct{}
+	onVBlank         func()
+	colorCorrection  atomic.Bool
 }
 
 const (
@@ -69,6 +74,9 @@ func (p *PPU) TClock() {
 		p.registers.LCDStatus.PPUMode = io.VBlank
 		if p.registers.LY == visibleLines {
 			p.interrupt.VBlank = true
+			if p.lineCycleCounter == 0 && p.onVBlank != nil {
+				p.onVBlank()
+			}
 		}
 	} else {
 		switch p.lineCycleCounter {
@@ -114,25 +122,73 @@ func (p *PPU) DisplayClock() {
 
 	// Render layers.
 	bgImg := bgLayer.Image()
-	finalImg := image.NewPaletted(screenRect, monochrome.Palette)
+	finalImg := image.NewPaletted(screenRect, p.palette())
 
 	// Start with the background.
 	copy(finalImg.Pix, bgImg.(*image.Paletted).Pix)
+	priority := bgLayer.Priority()
 
-	// Composite window layer (if enabled).
+	// Composite window layer (if enabled). It's opaque, so color 0 replaces the background too.
 	if p.registers.LCDControl.EnableWindow {
-		winImg := winLayer.Image()
-		compositeImage(finalImg, winImg.(*image.Paletted))
+		winImg := winLayer.Image().(*image.Paletted)
+		winPriority := winLayer.Priority()
+		area := winLayer.Bounds()
+		for y := area.Min.Y; y < area.Max.Y; y++ {
+			start, end := winImg.PixOffset(area.Min.X, y), winImg.PixOffset(area.Max.X, y)
+			copy(finalImg.Pix[start:end], winImg.Pix[start:end])
+			if priority != nil && winPriority != nil {
+				copy(priority[start:end], winPriority[start:end])
+			}
+		}
 	}
 
-	// Composite sprite layer.
+	// Composite sprite layer, under BG tiles with the CGB priority attribute.
 	spriteImg := spriteLayer.Image()
-	compositeImage(finalImg, spriteImg.(*image.Paletted))
+	if priority != nil {
+		for i, pix := range spriteImg.(*image.Paletted).Pix {
+			if pix != 0 && !priority[i] {
+				finalImg.Pix[i] = pix
+			}
+		}
+	} else {
+		compositeImage(finalImg, spriteImg.(*image.Paletted))
+	}
 
 	// Set the composite image as the PPU output.
 	p.image = finalImg
 }
 
+// palette returns the colors of the pixel values drawn by the layers.
+// In CGB mode, that's the BG palette RAM followed by the sprite palette RAM, as they are at the time of drawing.
+func (p *PPU) palette() color.Palette {
+	if !p.registers.CGBMode {
+		return monochrome.Palette
+	}
+
+	convert := cgb.RGBA
+	if p.colorCorrection.Load() {
+		convert = cgb.Corrected
+	}
+
+	pal := make(color.Palette, layers.CGB_PALETTE_SIZE)
+	for i := range layers.OBJ_PALETTE_BASE {
+		number, c := i/layers.COLORS_PER_PALETTE, i%layers.COLORS_PER_PALETTE
+		pal[i] = convert(p.registers.BGPalettes.Color(number, c))
+		pal[layers.OBJ_PALETTE_BASE+i] = convert(p.registers.OBJPalettes.Color(number, c))
+	}
+	return pal
+}
+
+// SetColorCorrection sets whether CGB colors are adjusted to look like they do on the CGB's LCD, rather than shown as is.
+func (p *PPU) SetColorCorrection(on bool) {
+	p.colorCorrection.Store(on)
+}
+
+// OnVBlank registers a function that is called at the start of every VBlank.
+func (p *PPU) OnVBlank(f func()) {
+	p.onVBlank = f
+}
+
 func (p *PPU) Image() image.Image {
 	return p.image
 }
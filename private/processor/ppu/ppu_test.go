@@ -6,9 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/colecrouter/gameboy-go/private/display/cgb"
 	"github.com/colecrouter/gameboy-go/private/memory"
 	"github.com/colecrouter/gameboy-go/private/memory/io"
 	"github.com/colecrouter/gameboy-go/private/memory/vram"
+	"github.com/colecrouter/gameboy-go/private/memory/vram/layers"
 	"github.com/colecrouter/gameboy-go/private/system"
 )
 
@@ -57,3 +59,107 @@ func TestPPU(t *testing.T) {
 	time.Sleep(500 * time.Millisecond)
 	fmt.Println("Test complete. Check debug logs and display output.")
 }
+
+func TestPPUCGBAttributes(t *testing.T) {
+	vramModule := &vram.VRAM{}
+	regs := &io.Registers{CGBMode: true}
+	vramModule.ConnectBankSelect(&regs.VRAMBank1)
+	oamModule := memory.NewOAM(vramModule, &regs.LCDControl.Sprites8x16)
+
+	ppuUnit := NewPPU(&system.Broadcaster{}, vramModule, oamModule, regs, &io.Interrupt{})
+	regs.LCDControl.Use8000Method = true
+	regs.LCDControl.EnableBackgroundAndWindow = true
+
+	// Bank 0 tile 0 is the dummy tile, bank 1 tile 0 is a bar of color 1 on the left
+	for i, b := range dummyTileData {
+		vramModule.Write(uint16(i), b)
+	}
+	regs.VRAMBank1 = true
+	vramModule.Write(0x0000, 0xF0)
+
+	// Map entry 0: palette 1, drawn over sprites. Map entry 1: bank 1, flipped, palette 2
+	vramModule.Write(0x1800, 0b1000_0001)
+	vramModule.Write(0x1801, 0b0010_1010)
+	regs.VRAMBank1 = false
+
+	// Sprite at the top left using bank 1 tile 0 and palette 3
+	for i, b := range []uint8{16, 8, 0, 0b0000_1011} {
+		oamModule.Write(uint16(i), b)
+	}
+
+	ppuUnit.DisplayClock()
+	got := ppuUnit.image.Pix[:16]
+	// The sprite only shows where the BG is color 0
+	expected := []uint8{45, 6, 7, 7, 7, 7, 6, 4, 8, 8, 8, 8, 9, 9, 9, 9}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("with BG priority: expected\n\t%v\n—got\n\t%v", expected, got)
+	}
+
+	// LCDC bit 0 turns the priority attribute off
+	regs.LCDControl.EnableBackgroundAndWindow = false
+	ppuUnit.DisplayClock()
+	got = ppuUnit.image.Pix[:16]
+	expected = []uint8{45, 45, 45, 45, 7, 7, 6, 4, 8, 8, 8, 8, 9, 9, 9, 9}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("without BG priority: expected\n\t%v\n—got\n\t%v", expected, got)
+	}
+}
+
+func TestPPUCGBColors(t *testing.T) {
+	vramModule := &vram.VRAM{}
+	regs := &io.Registers{CGBMode: true}
+	oamModule := memory.NewOAM(vramModule, &regs.LCDControl.Sprites8x16)
+	ppuUnit := NewPPU(&system.Broadcaster{}, vramModule, oamModule, regs, &io.Interrupt{})
+
+	// BG palette 0 color 0 is red, sprite palette 2 color 3 is blue
+	regs.BGPalettes.RAM[0], regs.BGPalettes.RAM[1] = 0x1F, 0x00
+	regs.OBJPalettes.RAM[2*8+3*2], regs.OBJPalettes.RAM[2*8+3*2+1] = 0x00, 0x7C
+
+	ppuUnit.DisplayClock()
+	if got := ppuUnit.image.At(0, 0); got != cgb.RGBA(0x001F) {
+		t.Errorf("BG pixel = %v, want %v", got, cgb.RGBA(0x001F))
+	}
+	if got := ppuUnit.image.Palette[layers.OBJ_PALETTE_BASE+2*4+3]; got != cgb.RGBA(0x7C00) {
+		t.Errorf("sprite palette 2 color 3 = %v, want %v", got, cgb.RGBA(0x7C00))
+	}
+
+	ppuUnit.SetColorCorrection(true)
+	ppuUnit.DisplayClock()
+	if got := ppuUnit.image.At(0, 0); got != cgb.Corrected(0x001F) {
+		t.Errorf("corrected BG pixel = %v, want %v", got, cgb.Corrected(0x001F))
+	}
+}
+
+func TestPPUCGBWindowColor0(t *testing.T) {
+	vramModule := &vram.VRAM{}
+	regs := &io.Registers{CGBMode: true}
+	oamModule := memory.NewOAM(vramModule, &regs.LCDControl.Sprites8x16)
+	ppuUnit := NewPPU(&system.Broadcaster{}, vramModule, oamModule, regs, &io.Interrupt{})
+	regs.LCDControl.Use8000Method = true
+	regs.LCDControl.EnableBackgroundAndWindow = true
+	regs.LCDControl.EnableWindow = true
+	regs.LCDControl.WindowUseSecondTileMap = true
+
+	// Tile 1 is solid color 3. The background is made of it, the window of blank tile 0
+	for i := range 16 {
+		vramModule.Write(0x0010+uint16(i), 0xFF)
+	}
+	for i := range 32 {
+		vramModule.Write(0x1800+uint16(i), 1)
+	}
+	regs.WindowX, regs.WindowY = 7+8, 0
+
+	// A sprite of tile 1 half over the window
+	for i, b := range []uint8{16, 8 + 12, 1, 0} {
+		oamModule.Write(uint16(i), b)
+	}
+
+	ppuUnit.DisplayClock()
+	got := ppuUnit.image.Pix[:16]
+	// The window's color 0 hides the background, but not the sprite
+	sprite := uint8(layers.OBJ_PALETTE_BASE + 3)
+	expected := []uint8{3, 3, 3, 3, 3, 3, 3, 3, 0, 0, 0, 0, sprite, sprite, sprite, sprite}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected\n\t%v\n—got\n\t%v", expected, got)
+	}
+}
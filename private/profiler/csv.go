@@ -0,0 +1,67 @@
+package profiler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteCSV exports every address that was accessed, one row each.
+// Cartridge ROM addresses get a row per bank, with the bank in the second column; other rows leave it empty.
+func (p *Profiler) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"address", "bank", "reads", "writes", "executes"}); err != nil {
+		return err
+	}
+
+	row := func(addr uint16, bank string, counts [accessKinds]uint64) error {
+		if counts == [accessKinds]uint64{} {
+			return nil
+		}
+		return cw.Write([]string{
+			fmt.Sprintf("0x%04X", addr),
+			bank,
+			strconv.FormatUint(counts[READ], 10),
+			strconv.FormatUint(counts[WRITE], 10),
+			strconv.FormatUint(counts[EXECUTE], 10),
+		})
+	}
+
+	banked := false
+	for w := range p.banks {
+		for bank := range p.banks[w] {
+			counts := p.banks[w][bank].Load()
+			if counts == nil {
+				continue
+			}
+			banked = true
+			for offset := range counts {
+				var c [accessKinds]uint64
+				for kind := range c {
+					c[kind] = counts[offset][kind].Load()
+				}
+				if err := row(uint16(w*ROM_BANK_SIZE+offset), strconv.Itoa(bank), c); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for addr := range p.addrs {
+		// ROM was already written per bank
+		if banked && addr <= ROM_END {
+			continue
+		}
+		var c [accessKinds]uint64
+		for kind := range c {
+			c[kind] = p.addrs[addr][kind].Load()
+		}
+		if err := row(uint16(addr), "", c); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,69 @@
+package profiler
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// ALL combines every kind of access in the heatmap.
+const ALL = -1
+
+// HEATMAP_SIZE is the width and height of the heatmap. Each pixel is one address, each row one 256-byte page.
+const HEATMAP_SIZE = 256
+
+// HeatmapPalette runs from black for untouched addresses, through blue and red, to white for the hottest.
+var HeatmapPalette = func() color.Palette {
+	stops := []color.RGBA{
+		{0x00, 0x00, 0x00, 0xFF},
+		{0x20, 0x20, 0xA0, 0xFF},
+		{0xC0, 0x20, 0x40, 0xFF},
+		{0xFF, 0xC0, 0x20, 0xFF},
+		{0xFF, 0xFF, 0xFF, 0xFF},
+	}
+
+	const steps = 8 // Colors between each pair of stops
+	p := color.Palette{stops[0]}
+	for i := 0; i < len(stops)-1; i++ {
+		from, to := stops[i], stops[i+1]
+		for s := 1; s <= steps; s++ {
+			mix := func(a, b uint8) uint8 {
+				return uint8(int(a) + (int(b)-int(a))*s/steps)
+			}
+			p = append(p, color.RGBA{mix(from.R, to.R), mix(from.G, to.G), mix(from.B, to.B), 0xFF})
+		}
+	}
+	return p
+}()
+
+// Heatmap draws the counts of one kind of access, or ALL, as an image.
+// Counts are log-scaled, so rarely touched addresses still stand out from untouched ones.
+func (p *Profiler) Heatmap(kind int) *image.Paletted {
+	img := image.NewPaletted(image.Rect(0, 0, HEATMAP_SIZE, HEATMAP_SIZE), HeatmapPalette)
+
+	var counts [0x10000]uint64
+	var hottest uint64
+	for addr := range counts {
+		if kind == ALL {
+			for k := range accessKinds {
+				counts[addr] += p.addrs[addr][k].Load()
+			}
+		} else {
+			counts[addr] = p.addrs[addr][kind].Load()
+		}
+		hottest = max(hottest, counts[addr])
+	}
+	if hottest == 0 {
+		return img
+	}
+
+	scale := float64(len(HeatmapPalette)-2) / math.Log1p(float64(hottest))
+	for addr, count := range counts {
+		if count == 0 {
+			continue
+		}
+		// Index 0 is reserved for untouched addresses
+		img.Pix[addr] = uint8(1 + math.Round(math.Log1p(float64(count))*scale))
+	}
+	return img
+}
@@ -0,0 +1,154 @@
+package profiler
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/colecrouter/gameboy-go/private/memory"
+)
+
+const (
+	ROM_END       = 0x7FFF
+	ROM_BANK_SIZE = 0x4000
+	ROM_WINDOWS   = 2   // 0x0000-0x3FFF and 0x4000-0x7FFF, either of which can show any bank on some mappers
+	MAX_ROM_BANKS = 512 // MBC5's limit
+)
+
+// Kinds of access the profiler counts, used to index the counters.
+const (
+	READ = iota
+	WRITE
+	EXECUTE
+	accessKinds
+)
+
+var kindOf = map[memory.AccessType]int{
+	memory.ACCESS_READ:    READ,
+	memory.ACCESS_WRITE:   WRITE,
+	memory.ACCESS_EXECUTE: EXECUTE,
+}
+
+type counters [accessKinds]atomic.Uint64
+
+// bankCounters are the counts for one ROM bank in one window, by offset into the bank.
+type bankCounters [ROM_BANK_SIZE]counters
+
+// Profiler counts reads, writes and instruction fetches for every address on the bus.
+// Cartridge ROM accesses are also counted per bank, since the same address reaches different code as banks switch.
+type Profiler struct {
+	bus  *memory.Bus
+	bank func(addr uint16) uint
+
+	mu   sync.Mutex
+	hook memory.HookID
+	on   bool
+
+	addrs [0x10000]counters
+	banks [ROM_WINDOWS][MAX_ROM_BANKS]atomic.Pointer[bankCounters] // By window, then bank. Allocated the first time each is touched
+}
+
+// New creates a profiler for a bus. bank reports which ROM bank is mapped at a cartridge ROM address, and may be nil.
+func New(bus *memory.Bus, bank func(addr uint16) uint) *Profiler {
+	return &Profiler{bus: bus, bank: bank}
+}
+
+// Start begins counting accesses. Counts carry on from where Stop left them.
+func (p *Profiler) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.on {
+		return
+	}
+	p.hook = p.bus.AddHook(memory.Hook{
+		Types: memory.ACCESS_READ | memory.ACCESS_WRITE | memory.ACCESS_EXECUTE,
+		Start: 0x0000,
+		End:   0xFFFF,
+		Func:  p.record,
+	})
+	p.on = true
+}
+
+// Stop stops counting, removing the profiler's cost from the bus.
+func (p *Profiler) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.on {
+		return
+	}
+	p.bus.RemoveHook(p.hook)
+	p.on = false
+}
+
+// Running reports whether the profiler is counting.
+func (p *Profiler) Running() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.on
+}
+
+// Reset clears all counts.
+func (p *Profiler) Reset() {
+	for i := range p.addrs {
+		for k := range p.addrs[i] {
+			p.addrs[i][k].Store(0)
+		}
+	}
+	for w := range p.banks {
+		for i := range p.banks[w] {
+			p.banks[w][i].Store(nil)
+		}
+	}
+}
+
+func (p *Profiler) record(a memory.Access) {
+	kind := kindOf[a.Type]
+	p.addrs[a.Addr][kind].Add(1)
+
+	if a.Addr > ROM_END || p.bank == nil {
+		return
+	}
+	slot := &p.banks[a.Addr/ROM_BANK_SIZE][p.bank(a.Addr)%MAX_ROM_BANKS]
+	counts := slot.Load()
+	if counts == nil {
+		slot.CompareAndSwap(nil, new(bankCounters))
+		counts = slot.Load()
+	}
+	counts[a.Addr%ROM_BANK_SIZE][kind].Add(1)
+}
+
+// Count returns how many accesses of a kind addr has seen.
+func (p *Profiler) Count(kind int, addr uint16) uint64 {
+	return p.addrs[addr][kind].Load()
+}
+
+// BankCount returns how many accesses of a kind an offset into a ROM bank has seen, through either window.
+func (p *Profiler) BankCount(kind int, bank uint, offset uint16) uint64 {
+	var n uint64
+	for w := range p.banks {
+		if counts := p.banks[w][bank%MAX_ROM_BANKS].Load(); counts != nil {
+			n += counts[offset%ROM_BANK_SIZE][kind].Load()
+		}
+	}
+	return n
+}
+
+// BankTotals returns the total accesses of each kind for every ROM bank that was touched.
+func (p *Profiler) BankTotals() map[uint][accessKinds]uint64 {
+	totals := map[uint][accessKinds]uint64{}
+	for w := range p.banks {
+		for bank := range p.banks[w] {
+			counts := p.banks[w][bank].Load()
+			if counts == nil {
+				continue
+			}
+			sum := totals[uint(bank)]
+			for offset := range counts {
+				for kind := range sum {
+					sum[kind] += counts[offset][kind].Load()
+				}
+			}
+			totals[uint(bank)] = sum
+		}
+	}
+	return totals
+}
@@ -0,0 +1,159 @@
+package profiler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/memory"
+)
+
+func newTestBus() (*memory.Bus, *uint) {
+	bus := &memory.Bus{}
+	bus.AddDevice(0x0000, 0x7FFF, &memory.Memory{Buffer: make([]byte, 0x8000)})
+	bus.AddDevice(0xC000, 0xDFFF, &memory.Memory{Buffer: make([]byte, 0x2000)})
+	bank := uint(1)
+	return bus, &bank
+}
+
+func TestProfiler(t *testing.T) {
+	bus, bank := newTestBus()
+	p := New(bus, func(addr uint16) uint {
+		if addr < ROM_BANK_SIZE {
+			return 0
+		}
+		return *bank
+	})
+
+	// Nothing is counted until started
+	bus.Read(0xC000)
+	p.Start()
+	p.Start()
+
+	bus.Fetch(0x0150)
+	bus.Fetch(0x4000)
+	bus.Read(0x4000)
+	*bank = 2
+	bus.Read(0x4000)
+	bus.Write(0xC000, 1)
+	bus.Write(0xC000, 2)
+	bus.Read(0xC001)
+
+	p.Stop()
+	p.Stop()
+	bus.Read(0xC001)
+
+	tests := []struct {
+		kind  int
+		addr  uint16
+		count uint64
+	}{
+		{EXECUTE, 0x0150, 1},
+		{EXECUTE, 0x4000, 1},
+		{READ, 0x4000, 2},
+		{WRITE, 0xC000, 2},
+		{READ, 0xC000, 0},
+		{READ, 0xC001, 1},
+	}
+	for _, tt := range tests {
+		if got := p.Count(tt.kind, tt.addr); got != tt.count {
+			t.Errorf("Count(%d, %04X) = %d, want %d", tt.kind, tt.addr, got, tt.count)
+		}
+	}
+
+	if got := p.BankCount(READ, 1, 0x0000); got != 1 {
+		t.Errorf("bank 1 reads = %d, want 1", got)
+	}
+	if got := p.BankCount(READ, 2, 0x0000); got != 1 {
+		t.Errorf("bank 2 reads = %d, want 1", got)
+	}
+	if got := p.BankCount(READ, 3, 0x0000); got != 0 {
+		t.Errorf("untouched bank 3 reads = %d, want 0", got)
+	}
+
+	totals := p.BankTotals()
+	if len(totals) != 3 || totals[1][EXECUTE] != 1 || totals[2][READ] != 1 {
+		t.Errorf("got bank totals %v", totals)
+	}
+
+	p.Reset()
+	if p.Count(WRITE, 0xC000) != 0 || len(p.BankTotals()) != 0 {
+		t.Error("Reset didn't clear the counts")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	bus, bank := newTestBus()
+	p := New(bus, func(addr uint16) uint {
+		if addr < ROM_BANK_SIZE {
+			return 0
+		}
+		return *bank
+	})
+	p.Start()
+
+	bus.Fetch(0x0100)
+	bus.Read(0x4010)
+	*bank = 5
+	bus.Read(0x4010)
+	*bank = 0 // MBC5 can map bank 0 into the switchable window
+	bus.Read(0x4020)
+	bus.Write(0xD000, 0)
+
+	var buf bytes.Buffer
+	if err := p.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"address", "bank", "reads", "writes", "executes"},
+		{"0x0100", "0", "0", "0", "1"},
+		{"0x4020", "0", "1", "0", "0"},
+		{"0x4010", "1", "1", "0", "0"},
+		{"0x4010", "5", "1", "0", "0"},
+		{"0xD000", "", "0", "1", "0"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got rows %v, want %v", rows, want)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if rows[i][j] != want[i][j] {
+				t.Errorf("row %d = %v, want %v", i, rows[i], want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestHeatmap(t *testing.T) {
+	bus, _ := newTestBus()
+	p := New(bus, nil)
+	p.Start()
+
+	for range 100 {
+		bus.Read(0xC000)
+	}
+	bus.Read(0xC001)
+	bus.Write(0xC002, 0)
+
+	img := p.Heatmap(READ)
+	hot := img.ColorIndexAt(0x00, 0xC0)
+	warm := img.ColorIndexAt(0x01, 0xC0)
+	if hot != uint8(len(HeatmapPalette)-1) {
+		t.Errorf("hottest address got color %d, want %d", hot, len(HeatmapPalette)-1)
+	}
+	if warm == 0 || warm >= hot {
+		t.Errorf("address read once got color %d, want between 0 and %d", warm, hot)
+	}
+	if img.ColorIndexAt(0x02, 0xC0) != 0 {
+		t.Error("written address shows up in the read heatmap")
+	}
+	if p.Heatmap(ALL).ColorIndexAt(0x02, 0xC0) == 0 {
+		t.Error("written address missing from the combined heatmap")
+	}
+}
@@ -0,0 +1,33 @@
+package gamepak
+
+type CGBSupport uint8
+
+const CGB_NONE CGBSupport = 0x00     // DMG only
+const CGB_ENHANCED CGBSupport = 0x80 // Uses CGB features, but also works on DMG
+const CGB_ONLY CGBSupport = 0xC0     // Only works on CGB
+
+// CGBFlag returns whether the game supports Game Boy Color features, from 0x0143
+func (gp *GamePak) CGBFlag() CGBSupport {
+	switch val := gp.header(0x0143); val {
+	case uint8(CGB_ENHANCED), uint8(CGB_ONLY):
+		return CGBSupport(val)
+	default:
+		return CGB_NONE
+	}
+}
+
+func (c CGBSupport) String() string {
+	switch c {
+	case CGB_ENHANCED:
+		return "CGB enhanced"
+	case CGB_ONLY:
+		return "CGB only"
+	default:
+		return "DMG"
+	}
+}
+
+// SGBFlag returns whether the game supports Super Game Boy functions, from 0x0146
+func (gp *GamePak) SGBFlag() bool {
+	return gp.header(0x0146) == 0x03
+}
@@ -1,13 +1,15 @@
 package gamepak
 
-func (gp *GamePak) headerChecksum() uint8 {
-	return gp.buffer[0x014D]
+// HeaderChecksum returns the header checksum stored at 0x014D
+func (gp *GamePak) HeaderChecksum() uint8 {
+	return gp.header(0x014D)
 }
 
-func (gp *GamePak) globalChecksum() uint16 {
+// GlobalChecksum returns the global checksum stored at 0x014E-0x014F
+func (gp *GamePak) GlobalChecksum() uint16 {
 	var val uint16
-	val = uint16(gp.buffer[0x014E]) << 8
-	val |= uint16(gp.buffer[0x014F])
+	val = uint16(gp.header(0x014E)) << 8
+	val |= uint16(gp.header(0x014F))
 
 	return val
 }
@@ -15,10 +17,10 @@ func (gp *GamePak) globalChecksum() uint16 {
 func (gp *GamePak) ComputeHeaderChecksum() bool {
 	var sum uint8
 	for i := 0x0134; i <= 0x014C; i++ {
-		sum = sum - gp.buffer[i] - 1
+		sum = sum - gp.header(i) - 1
 	}
 
-	return sum == gp.headerChecksum()
+	return sum == gp.HeaderChecksum()
 }
 
 func (gp *GamePak) ComputeGlobalChecksum() bool {
@@ -30,5 +32,5 @@ func (gp *GamePak) ComputeGlobalChecksum() bool {
 		sum += uint16(gp.buffer[i])
 	}
 
-	return sum == gp.globalChecksum()
+	return sum == gp.GlobalChecksum()
 }
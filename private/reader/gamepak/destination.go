@@ -6,5 +6,16 @@ const JAPANESE DestinationCode = 0x00
 const NON_JAPANESE DestinationCode = 0x01
 
 func (gp *GamePak) DestinationCode() DestinationCode {
-	return DestinationCode(gp.buffer[0x014A])
+	return DestinationCode(gp.header(0x014A))
+}
+
+func (d DestinationCode) String() string {
+	switch d {
+	case JAPANESE:
+		return "Japan"
+	case NON_JAPANESE:
+		return "Overseas"
+	default:
+		return "Unknown"
+	}
 }
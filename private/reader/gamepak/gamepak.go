@@ -1,26 +1,62 @@
 package gamepak
 
+import "github.com/colecrouter/gameboy-go/private/memory"
+
+// HEADER_END is where the cartridge header ends. Images shorter than this are missing part of it, which reads as zeros.
+const HEADER_END = 0x150
+
 type GamePak struct {
 	initialized bool
 	buffer      []byte
 }
 
 func (g *GamePak) Read(addr uint16) uint8 {
-	if !g.initialized {
-		panic("GamePak not initialized")
+	if !g.initialized || int(addr) >= len(g.buffer) {
+		return memory.OPEN_BUS
 	}
 	val := g.buffer[addr]
 	return val
 }
 
 func (g *GamePak) Write(addr uint16, data uint8) {
-	if !g.initialized {
-		panic("GamePak not initialized")
+	if !g.initialized || int(addr) >= len(g.buffer) {
+		return
 	}
 	// TODO: Implement proper write protection
 	g.buffer[addr] = data
 }
 
+// ReadOffset returns the byte at an absolute offset into the ROM image.
+// Offsets past the end of the image wrap around, like unconnected address lines do.
+func (g *GamePak) ReadOffset(offset uint) uint8 {
+	if !g.initialized || len(g.buffer) == 0 {
+		return memory.OPEN_BUS
+	}
+	return g.buffer[offset%uint(len(g.buffer))]
+}
+
+// header returns a header byte, or 0 if the image is too short to have it.
+func (g *GamePak) header(addr int) uint8 {
+	if addr >= len(g.buffer) {
+		return 0
+	}
+	return g.buffer[addr]
+}
+
+// headerBytes returns a copy of the header bytes from start up to end, zero-filled past the end of the image.
+func (g *GamePak) headerBytes(start, end int) []byte {
+	b := make([]byte, end-start)
+	if start < len(g.buffer) {
+		copy(b, g.buffer[start:min(end, len(g.buffer))])
+	}
+	return b
+}
+
+// Size returns the size of the ROM image in bytes.
+func (g *GamePak) Size() uint {
+	return uint(len(g.buffer))
+}
+
 func NewGamePak(b []byte) *GamePak {
 	gp := &GamePak{buffer: b, initialized: true}
 	return gp
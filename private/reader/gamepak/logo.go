@@ -11,7 +11,7 @@ var NintendoLogo = [48]byte{
 
 func (g *GamePak) ValidateNintendoLogo() bool {
 	for i, b := range NintendoLogo {
-		if g.buffer[0x0104+i] != b {
+		if g.header(0x0104+i) != b {
 			return false
 		}
 	}
@@ -2,19 +2,19 @@ package gamepak
 
 // RomSize returns the size of the ROM in bytes
 func (gp *GamePak) RomSize() uint {
-	val := uint(gp.buffer[0x148])
+	val := uint(gp.header(0x148))
 	return (32 * 1024) * (1 << val)
 }
 
-// BankCount returns the number of ROM banks
+// BankCount returns the number of 16 KiB ROM banks
 func (gp *GamePak) BankCount() uint {
-	val := uint(gp.buffer[0x148])
-	return 1 << val
+	val := uint(gp.header(0x148))
+	return 2 << val
 }
 
 // RamSize returns the size of the RAM in bytes
 func (gp *GamePak) RamSize() uint {
-	val := uint(gp.buffer[0x149])
+	val := uint(gp.header(0x149))
 	switch val {
 	case 1:
 		return 2 * 1024
@@ -33,7 +33,7 @@ func (gp *GamePak) RamSize() uint {
 
 // RamBankCount returns the number of RAM banks
 func (gp *GamePak) RamBankCount() uint {
-	val := uint(gp.buffer[0x149])
+	val := uint(gp.header(0x149))
 	switch val {
 	case 2:
 		return 1
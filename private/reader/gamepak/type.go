@@ -1,5 +1,7 @@
 package gamepak
 
+import "fmt"
+
 type CartridgeType uint8
 
 const ROM_ONLY CartridgeType = 0x00
@@ -32,5 +34,56 @@ const HUC3 CartridgeType = 0xFE
 const HUC1_RAM_BATTERY CartridgeType = 0xFF
 
 func (gp *GamePak) CartridgeType() CartridgeType {
-	return CartridgeType(gp.buffer[0x147])
+	return CartridgeType(gp.header(0x147))
+}
+
+var cartridgeTypeNames = map[CartridgeType]string{
+	ROM_ONLY:                       "ROM ONLY",
+	MBC1:                           "MBC1",
+	MBC1_RAM:                       "MBC1+RAM",
+	MBC1_RAM_BATTERY:               "MBC1+RAM+BATTERY",
+	MBC2:                           "MBC2",
+	MBC2_BATTERY:                   "MBC2+BATTERY",
+	ROM_RAM:                        "ROM+RAM",
+	ROM_RAM_BATTERY:                "ROM+RAM+BATTERY",
+	MMM01:                          "MMM01",
+	MMM01_RAM:                      "MMM01+RAM",
+	MMM01_RAM_BATTERY:              "MMM01+RAM+BATTERY",
+	MBC3_TIMER_BATTERY:             "MBC3+TIMER+BATTERY",
+	MBC3_TIMER_RAM_BATTERY:         "MBC3+TIMER+RAM+BATTERY",
+	MBC3:                           "MBC3",
+	MBC3_RAM:                       "MBC3+RAM",
+	MBC3_RAM_BATTERY:               "MBC3+RAM+BATTERY",
+	MBC5:                           "MBC5",
+	MBC5_RAM:                       "MBC5+RAM",
+	MBC5_RAM_BATTERY:               "MBC5+RAM+BATTERY",
+	MBC5_RUMBLE:                    "MBC5+RUMBLE",
+	MBC5_RUMBLE_RAM:                "MBC5+RUMBLE+RAM",
+	MBC5_RUMBLE_RAM_BATTERY:        "MBC5+RUMBLE+RAM+BATTERY",
+	MBC6:                           "MBC6",
+	MBC7_SENSOR_RUMBLE_RAM_BATTERY: "MBC7+SENSOR+RUMBLE+RAM+BATTERY",
+	POCKET_CAMERA:                  "POCKET CAMERA",
+	BANDAI_TAMA5:                   "BANDAI TAMA5",
+	HUC3:                           "HuC3",
+	HUC1_RAM_BATTERY:               "HuC1+RAM+BATTERY",
+}
+
+func (t CartridgeType) String() string {
+	if name, ok := cartridgeTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("UNKNOWN (0x%02X)", uint8(t))
+}
+
+// HasBattery reports whether the cartridge keeps its RAM (and clock, if any) powered while switched off.
+func (t CartridgeType) HasBattery() bool {
+	switch t {
+	case MBC1_RAM_BATTERY, MBC2_BATTERY, ROM_RAM_BATTERY, MMM01_RAM_BATTERY,
+		MBC3_TIMER_BATTERY, MBC3_TIMER_RAM_BATTERY, MBC3_RAM_BATTERY,
+		MBC5_RAM_BATTERY, MBC5_RUMBLE_RAM_BATTERY, MBC7_SENSOR_RUMBLE_RAM_BATTERY,
+		POCKET_CAMERA, HUC1_RAM_BATTERY, HUC3:
+		return true
+	default:
+		return false
+	}
 }
@@ -0,0 +1,29 @@
+package mbc
+
+import "sync"
+
+// Accelerometer holds the tilt reported to cartridges with a motion sensor, in g along each axis.
+// Zero on both axes means the Game Boy is lying flat.
+type Accelerometer struct {
+	mu   sync.Mutex
+	x, y float64
+}
+
+// SetTilt sets the current acceleration along both axes.
+func (a *Accelerometer) SetTilt(x, y float64) {
+	a.mu.Lock()
+	a.x, a.y = x, y
+	a.mu.Unlock()
+}
+
+// Tilt returns the current acceleration along both axes.
+func (a *Accelerometer) Tilt() (x, y float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.x, a.y
+}
+
+// TiltSensor is implemented by mappers with an accelerometer.
+type TiltSensor interface {
+	ConnectAccelerometer(a *Accelerometer)
+}
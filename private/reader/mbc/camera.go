@@ -0,0 +1,222 @@
+package mbc
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+const (
+	cameraWidth  = 128
+	cameraHeight = 112
+
+	cameraRegisterCount = 0x36   // 0xA000-0xA035, mirrored every 0x80 bytes
+	cameraImageOffset   = 0x0100 // Captured tiles are written to RAM bank 0 from 0xA100
+)
+
+// Camera register indexes
+const (
+	camControl   = 0x00 // Bit 0 starts a capture and reads 1 while busy
+	camGain      = 0x01 // Bits 0-4 gain, bits 5-7 edge enhancement mode
+	camExposureH = 0x02
+	camExposureL = 0x03
+	camEdge      = 0x04 // Bits 4-6 edge enhancement ratio, bit 3 inverts the output
+	camMatrix    = 0x06 // 4x4 dithering matrix with 3 thresholds per pixel, 0xA006-0xA035
+)
+
+// PocketCamera is the Game Boy Camera mapper, with 1 MiB of ROM, 128 KiB of RAM and an image sensor.
+// Frames come from a pluggable ImageSource rather than a real sensor.
+// https://gbdev.io/pandocs/Gameboy_Camera.html
+type PocketCamera struct {
+	rom    *gamepak.GamePak
+	ram    []byte
+	source ImageSource
+
+	ramWritable bool  // 0x0000-0x1FFF - RAM can always be read
+	romBank     uint8 // 0x2000-0x3FFF - 6-bit ROM bank number
+	ramBank     uint8 // 0x4000-0x5FFF - Bit 4 maps the camera registers instead of RAM
+
+	registers [cameraRegisterCount]uint8
+}
+
+func NewPocketCamera(gp *gamepak.GamePak) *PocketCamera {
+	ram := newRAM(gp)
+	if len(ram) == 0 {
+		ram = make([]byte, 128*1024)
+	}
+	return &PocketCamera{rom: gp, ram: ram, romBank: 1}
+}
+
+// ConnectCamera sets where the sensor's frames come from.
+func (m *PocketCamera) ConnectCamera(src ImageSource) {
+	m.source = src
+}
+
+func (m *PocketCamera) ReadROM(addr uint16) uint8 {
+	return m.rom.ReadOffset(m.ROMBank(addr)*romBankSize + uint(addr&0x3FFF))
+}
+
+// ROMBank returns the ROM bank mapped at addr.
+func (m *PocketCamera) ROMBank(addr uint16) uint {
+	if addr < 0x4000 {
+		return 0
+	}
+	return uint(m.romBank)
+}
+
+func (m *PocketCamera) WriteROM(addr uint16, data uint8) {
+	switch {
+	case addr < 0x2000:
+		m.ramWritable = data&0x0F == 0x0A
+	case addr < 0x4000:
+		m.romBank = data & 0x3F
+	case addr < 0x6000:
+		m.ramBank = data & 0x1F
+	}
+}
+
+func (m *PocketCamera) ReadRAM(addr uint16) uint8 {
+	if m.cameraMapped() {
+		// Only the control register can be read back
+		if addr&0x7F == camControl {
+			return m.registers[camControl]
+		}
+		return 0x00
+	}
+	return m.ram[m.ramOffset(addr)]
+}
+
+func (m *PocketCamera) WriteRAM(addr uint16, data uint8) {
+	if m.cameraMapped() {
+		reg := addr & 0x7F
+		if reg >= cameraRegisterCount {
+			return
+		}
+		m.registers[reg] = data
+		if reg == camControl && data&0x01 != 0 {
+			m.capture()
+		}
+		return
+	}
+	if !m.ramWritable {
+		return
+	}
+	m.ram[m.ramOffset(addr)] = data
+}
+
+func (m *PocketCamera) cameraMapped() bool {
+	return m.ramBank&0x10 != 0
+}
+
+func (m *PocketCamera) ramOffset(addr uint16) int {
+	return (int(m.ramBank&0x0F)*ramBankSize + int(addr)) % len(m.ram)
+}
+
+// capture runs the sensor pipeline and writes the result into RAM bank 0 as 16x14 tiles.
+// The capture completes immediately, so the busy bit is cleared straight away.
+func (m *PocketCamera) capture() {
+	var frame image.Image
+	if m.source != nil {
+		frame = m.source.Frame()
+	}
+
+	var sensor [cameraHeight][cameraWidth]int
+	for y := 0; y < cameraHeight; y++ {
+		for x := 0; x < cameraWidth; x++ {
+			sensor[y][x] = m.expose(sample(frame, x, y))
+		}
+	}
+
+	for y := 0; y < cameraHeight; y++ {
+		for x := 0; x < cameraWidth; x++ {
+			val := sensor[y][x]
+			if m.registers[camGain]&0xE0 == 0xE0 {
+				val = m.enhanceEdges(&sensor, x, y)
+			}
+			if m.registers[camEdge]&(1<<3) != 0 {
+				val = 0xFF - val
+			}
+			m.plot(x, y, m.dither(val, x, y))
+		}
+	}
+
+	m.registers[camControl] &^= 0x01
+}
+
+// sample returns the luminance of the frame at the given sensor position, scaling the frame to fit.
+func sample(frame image.Image, x, y int) int {
+	if frame == nil {
+		return 0x80
+	}
+	b := frame.Bounds()
+	if b.Empty() {
+		return 0x80
+	}
+	px := b.Min.X + x*b.Dx()/cameraWidth
+	py := b.Min.Y + y*b.Dy()/cameraHeight
+	return int(color.GrayModel.Convert(frame.At(px, py)).(color.Gray).Y)
+}
+
+// expose applies the exposure time and gain. An exposure of 0x1000 with no gain leaves the value unchanged.
+func (m *PocketCamera) expose(val int) int {
+	exposure := int(m.registers[camExposureH])<<8 | int(m.registers[camExposureL])
+	gain := 0x10 + int(m.registers[camGain]&0x1F)
+	val = val * exposure / 0x1000 * gain / 0x10
+	return min(val, 0xFF)
+}
+
+var edgeRatios = [8]float64{0.5, 0.75, 1, 1.25, 2, 3, 4, 5}
+
+// enhanceEdges sharpens a pixel against its four neighbours.
+func (m *PocketCamera) enhanceEdges(sensor *[cameraHeight][cameraWidth]int, x, y int) int {
+	at := func(x, y int) int {
+		x = min(max(x, 0), cameraWidth-1)
+		y = min(max(y, 0), cameraHeight-1)
+		return sensor[y][x]
+	}
+
+	ratio := edgeRatios[m.registers[camEdge]>>4&0x07]
+	neighbours := at(x-1, y) + at(x+1, y) + at(x, y-1) + at(x, y+1)
+	val := float64(at(x, y)) + ratio*float64(4*at(x, y)-neighbours)
+	return min(max(int(val), 0), 0xFF)
+}
+
+// dither maps a value to a 2-bit colour using the matrix thresholds for the pixel position.
+func (m *PocketCamera) dither(val, x, y int) uint8 {
+	thresholds := m.registers[camMatrix+((y&3)*4+(x&3))*3:]
+	switch {
+	case val < int(thresholds[0]):
+		return 3
+	case val < int(thresholds[1]):
+		return 2
+	case val < int(thresholds[2]):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// plot writes a 2-bit colour into the captured tile data.
+func (m *PocketCamera) plot(x, y int, c uint8) {
+	tileIndex := (y/8)*(cameraWidth/8) + x/8
+	offset := cameraImageOffset + tileIndex*16 + (y%8)*2
+	bit := uint8(1) << (7 - x%8)
+
+	m.ram[offset] &^= bit
+	m.ram[offset+1] &^= bit
+	if c&0x01 != 0 {
+		m.ram[offset] |= bit
+	}
+	if c&0x02 != 0 {
+		m.ram[offset+1] |= bit
+	}
+}
+
+func (m *PocketCamera) SaveData() []byte {
+	return copyRAM(m.ram)
+}
+
+func (m *PocketCamera) LoadSaveData(data []byte) {
+	copy(m.ram, data)
+}
@@ -0,0 +1,59 @@
+package mbc
+
+import (
+	"image"
+	_ "image/jpeg" // Register JPEG decoding for LoadImage
+	_ "image/png"  // Register PNG decoding for LoadImage
+	"os"
+	"sync"
+)
+
+// ImageSource supplies the frames seen by the Game Boy Camera's sensor.
+// Frame is called once per capture.
+type ImageSource interface {
+	Frame() image.Image
+}
+
+// StaticImage is a source that always shows the same picture.
+type StaticImage struct {
+	Image image.Image
+}
+
+func (s *StaticImage) Frame() image.Image {
+	return s.Image
+}
+
+// ImageSequence is a source that advances to the next picture on every capture, looping at the end.
+type ImageSequence struct {
+	mu     sync.Mutex
+	images []image.Image
+	next   int
+}
+
+func NewImageSequence(images ...image.Image) *ImageSequence {
+	return &ImageSequence{images: images}
+}
+
+func (s *ImageSequence) Frame() image.Image {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.images) == 0 {
+		return nil
+	}
+	img := s.images[s.next]
+	s.next = (s.next + 1) % len(s.images)
+	return img
+}
+
+// LoadImage decodes a PNG or JPEG file for use as a camera source.
+func LoadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	return img, err
+}
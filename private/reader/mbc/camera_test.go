@@ -0,0 +1,103 @@
+package mbc
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+// newTestCamera returns a camera with unity exposure and an even dithering matrix.
+func newTestCamera(src ImageSource) *PocketCamera {
+	m := NewPocketCamera(gamepak.NewGamePak(newTestROM(gamepak.POCKET_CAMERA, 64, 4)))
+	m.ConnectCamera(src)
+
+	m.WriteROM(0x4000, 0x10)
+	m.WriteRAM(camExposureH, 0x10)
+	m.WriteRAM(camExposureL, 0x00)
+	for i := 0; i < 16; i++ {
+		m.WriteRAM(camMatrix+uint16(i*3), 0x40)
+		m.WriteRAM(camMatrix+uint16(i*3)+1, 0x80)
+		m.WriteRAM(camMatrix+uint16(i*3)+2, 0xC0)
+	}
+	return m
+}
+
+func TestPocketCamera_Capture(t *testing.T) {
+	// Left half black, right half white
+	img := image.NewGray(image.Rect(0, 0, 256, 224))
+	for y := 0; y < 224; y++ {
+		for x := 128; x < 256; x++ {
+			img.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	}
+
+	m := newTestCamera(&StaticImage{Image: img})
+	m.WriteRAM(camControl, 0x01)
+
+	if got := m.ReadRAM(camControl); got&0x01 != 0 {
+		t.Errorf("capture still busy: got 0x%02X", got)
+	}
+
+	m.WriteROM(0x4000, 0x00)
+	for row := uint16(0); row < 8; row++ {
+		black := cameraImageOffset + row*2         // Tile 0
+		white := cameraImageOffset + 15*16 + row*2 // Tile 15, top right
+		if m.ReadRAM(black) != 0xFF || m.ReadRAM(black+1) != 0xFF {
+			t.Errorf("black tile row %d: got 0x%02X%02X, want 0xFFFF", row, m.ReadRAM(black), m.ReadRAM(black+1))
+		}
+		if m.ReadRAM(white) != 0x00 || m.ReadRAM(white+1) != 0x00 {
+			t.Errorf("white tile row %d: got 0x%02X%02X, want 0x0000", row, m.ReadRAM(white), m.ReadRAM(white+1))
+		}
+	}
+}
+
+func TestPocketCamera_Dithering(t *testing.T) {
+	gray := image.NewUniform(color.Gray{Y: 0x90})
+	m := newTestCamera(&StaticImage{Image: gray})
+
+	// Raise the thresholds for even columns so they render darker
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x += 2 {
+			m.WriteRAM(camMatrix+uint16((y*4+x)*3)+1, 0xA0)
+		}
+	}
+	m.WriteRAM(camControl, 0x01)
+
+	m.WriteROM(0x4000, 0x00)
+	low, high := m.ReadRAM(cameraImageOffset), m.ReadRAM(cameraImageOffset+1)
+	// Even columns are colour 2, odd columns colour 1
+	if low != 0x55 || high != 0xAA {
+		t.Errorf("got planes 0x%02X/0x%02X, want 0x55/0xAA", low, high)
+	}
+}
+
+func TestPocketCamera_Sequence(t *testing.T) {
+	black := image.NewUniform(color.Black)
+	white := image.NewUniform(color.White)
+	m := newTestCamera(NewImageSequence(black, white))
+
+	for i, want := range []uint8{0xFF, 0x00, 0xFF} {
+		m.WriteROM(0x4000, 0x10)
+		m.WriteRAM(camControl, 0x01)
+		m.WriteROM(0x4000, 0x00)
+		if got := m.ReadRAM(cameraImageOffset); got != want {
+			t.Errorf("capture %d: got 0x%02X, want 0x%02X", i, got, want)
+		}
+	}
+}
+
+func TestPocketCamera_RAMWriteProtect(t *testing.T) {
+	m := NewPocketCamera(gamepak.NewGamePak(newTestROM(gamepak.POCKET_CAMERA, 64, 4)))
+
+	m.WriteRAM(0x0000, 0x12)
+	if got := m.ReadRAM(0x0000); got != 0x00 {
+		t.Errorf("write-protected RAM: got 0x%02X, want 0x00", got)
+	}
+	m.WriteROM(0x0000, 0x0A)
+	m.WriteRAM(0x0000, 0x12)
+	if got := m.ReadRAM(0x0000); got != 0x12 {
+		t.Errorf("got 0x%02X, want 0x12", got)
+	}
+}
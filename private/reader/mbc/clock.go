@@ -0,0 +1,15 @@
+package mbc
+
+import "time"
+
+// Clock is the time source for cartridge real-time clocks.
+// Tests can supply their own to fast-forward time.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
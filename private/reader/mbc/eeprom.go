@@ -0,0 +1,189 @@
+package mbc
+
+import "encoding/binary"
+
+type eepromState uint8
+
+const (
+	eepromIdle    eepromState = iota // Waiting for a start bit
+	eepromCommand                    // Shifting in the opcode and address
+	eepromRead                       // Shifting out data
+	eepromWrite                      // Shifting in data to write
+	eepromDone                       // Command finished, waiting for CS to drop
+)
+
+// 93LC56 opcodes, following the start bit
+const (
+	eepromOpExtended = 0b00 // EWDS, WRAL, ERAL or EWEN, selected by the top address bits
+	eepromOpWrite    = 0b01
+	eepromOpRead     = 0b10
+	eepromOpErase    = 0b11
+)
+
+// eeprom is a 93LC56 serial EEPROM in 128x16-bit mode, as used for saves by MBC7.
+// It is driven by bit-banging chip select, clock and data in, and answers on data out.
+type eeprom struct {
+	words [128]uint16
+
+	cs, clk, di, do bool
+
+	state        eepromState
+	shift        uint32
+	bits         int
+	addr         uint8
+	writeAll     bool
+	writeEnabled bool
+}
+
+func newEEPROM() *eeprom {
+	e := &eeprom{do: true}
+	for i := range e.words {
+		e.words[i] = 0xFFFF
+	}
+	return e
+}
+
+// read returns the pin states: bit 7 CS, bit 6 CLK, bit 1 DI, bit 0 DO.
+func (e *eeprom) read() uint8 {
+	var val uint8
+	if e.cs {
+		val |= 1 << 7
+	}
+	if e.clk {
+		val |= 1 << 6
+	}
+	if e.di {
+		val |= 1 << 1
+	}
+	if e.do {
+		val |= 1 << 0
+	}
+	return val
+}
+
+// write sets the pin states, using the same layout as read.
+func (e *eeprom) write(val uint8) {
+	cs := val&(1<<7) != 0
+	clk := val&(1<<6) != 0
+	di := val&(1<<1) != 0
+
+	if !cs {
+		// Dropping CS aborts whatever was in progress
+		e.state = eepromIdle
+	} else if clk && !e.clk {
+		e.clock(di)
+	}
+
+	e.cs, e.clk, e.di = cs, clk, di
+}
+
+// clock handles a rising clock edge while the chip is selected.
+func (e *eeprom) clock(di bool) {
+	var bit uint32
+	if di {
+		bit = 1
+	}
+
+	switch e.state {
+	case eepromIdle:
+		if di {
+			e.state = eepromCommand
+			e.shift, e.bits = 0, 0
+		}
+	case eepromCommand:
+		e.shift = e.shift<<1 | bit
+		e.bits++
+		if e.bits == 10 {
+			e.decode(uint8(e.shift>>8), uint8(e.shift))
+		}
+	case eepromRead:
+		word := e.words[e.addr&0x7F]
+		e.do = word&(1<<(15-e.bits)) != 0
+		e.bits++
+		if e.bits == 16 {
+			// Keep clocking to read sequential words
+			e.addr++
+			e.bits = 0
+		}
+	case eepromWrite:
+		e.shift = e.shift<<1 | bit
+		e.bits++
+		if e.bits == 16 {
+			if e.writeAll {
+				for i := range e.words {
+					e.words[i] = uint16(e.shift)
+				}
+			} else {
+				e.words[e.addr&0x7F] = uint16(e.shift)
+			}
+			e.finish()
+		}
+	}
+}
+
+func (e *eeprom) decode(op, addr uint8) {
+	e.addr = addr
+	e.shift, e.bits = 0, 0
+
+	switch op {
+	case eepromOpRead:
+		// A dummy zero bit comes before the data
+		e.state = eepromRead
+		e.do = false
+	case eepromOpWrite:
+		e.writeAll = false
+		e.startWrite()
+	case eepromOpErase:
+		if e.writeEnabled {
+			e.words[addr&0x7F] = 0xFFFF
+		}
+		e.finish()
+	case eepromOpExtended:
+		switch addr >> 6 {
+		case 0b00: // EWDS
+			e.writeEnabled = false
+			e.finish()
+		case 0b01: // WRAL
+			e.writeAll = true
+			e.startWrite()
+		case 0b10: // ERAL
+			if e.writeEnabled {
+				for i := range e.words {
+					e.words[i] = 0xFFFF
+				}
+			}
+			e.finish()
+		case 0b11: // EWEN
+			e.writeEnabled = true
+			e.finish()
+		}
+	}
+}
+
+func (e *eeprom) startWrite() {
+	if !e.writeEnabled {
+		e.finish()
+		return
+	}
+	e.state = eepromWrite
+}
+
+// finish ends a command. Programming is instant, so DO reports ready straight away.
+func (e *eeprom) finish() {
+	e.state = eepromDone
+	e.do = true
+}
+
+func (e *eeprom) bytes() []byte {
+	data := make([]byte, 0, len(e.words)*2)
+	for _, w := range e.words {
+		data = binary.LittleEndian.AppendUint16(data, w)
+	}
+	return data
+}
+
+func (e *eeprom) load(data []byte) {
+	for i := 0; i < len(e.words) && i*2+1 < len(data); i++ {
+		e.words[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+}
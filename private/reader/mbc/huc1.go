@@ -0,0 +1,83 @@
+package mbc
+
+import "github.com/colecrouter/gameboy-go/private/reader/gamepak"
+
+// HuC1 is Hudson's MBC1-like mapper with an infrared port.
+// https://gbdev.io/pandocs/HuC1.html
+type HuC1 struct {
+	rom *gamepak.GamePak
+	ram []byte
+	ir  irPort
+
+	irMode  bool  // 0x0000-0x1FFF - 0x0E maps the IR port at 0xA000, anything else maps RAM
+	romBank uint8 // 0x2000-0x3FFF - 6-bit ROM bank number, 0 is treated as 1
+	ramBank uint8 // 0x4000-0x5FFF - 2-bit RAM bank number
+}
+
+func NewHuC1(gp *gamepak.GamePak) *HuC1 {
+	return &HuC1{rom: gp, ram: newRAM(gp), romBank: 1}
+}
+
+// ConnectInfrared connects the cartridge's IR port to a peer.
+func (m *HuC1) ConnectInfrared(p InfraredPeer) {
+	m.ir.peer = p
+}
+
+func (m *HuC1) ReadROM(addr uint16) uint8 {
+	return m.rom.ReadOffset(m.ROMBank(addr)*romBankSize + uint(addr&0x3FFF))
+}
+
+// ROMBank returns the ROM bank mapped at addr.
+func (m *HuC1) ROMBank(addr uint16) uint {
+	if addr < 0x4000 {
+		return 0
+	}
+	return uint(m.romBank)
+}
+
+func (m *HuC1) WriteROM(addr uint16, data uint8) {
+	switch {
+	case addr < 0x2000:
+		m.irMode = data&0x0F == 0x0E
+	case addr < 0x4000:
+		m.romBank = data & 0x3F
+		if m.romBank == 0 {
+			m.romBank = 1
+		}
+	case addr < 0x6000:
+		m.ramBank = data & 0x03
+	}
+}
+
+func (m *HuC1) ReadRAM(addr uint16) uint8 {
+	if m.irMode {
+		return m.ir.read()
+	}
+	if len(m.ram) == 0 {
+		return 0xFF
+	}
+	return m.ram[m.ramOffset(addr)]
+}
+
+func (m *HuC1) WriteRAM(addr uint16, data uint8) {
+	if m.irMode {
+		m.ir.write(data)
+		return
+	}
+	if len(m.ram) == 0 {
+		return
+	}
+	m.ram[m.ramOffset(addr)] = data
+}
+
+func (m *HuC1) ramOffset(addr uint16) int {
+	return (int(m.ramBank)*ramBankSize + int(addr)) % len(m.ram)
+}
+
+func (m *HuC1) SaveData() []byte {
+	return copyRAM(m.ram)
+}
+
+func (m *HuC1) LoadSaveData(data []byte) {
+	copy(m.ram, data)
+}
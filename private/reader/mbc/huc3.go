@@ -0,0 +1,246 @@
+package mbc
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+// HuC3 modes, selected by writing to 0x0000-0x1FFF.
+const (
+	huc3ReadOnlyRAM  = 0x0
+	huc3RAM          = 0xA
+	huc3CommandWrite = 0xB
+	huc3CommandRead  = 0xC
+	huc3Semaphore    = 0xD
+	huc3Infrared     = 0xE
+)
+
+// HuC3 commands, written to 0xA000 in the upper nibble while in command mode.
+const (
+	huc3Read     = 0x1 // Read the register at the address, then increment it
+	huc3Set      = 0x2 // Write the argument to the register at the address
+	huc3Write    = 0x3 // Write the argument to the register at the address, then increment it
+	huc3AddrLow  = 0x4 // Set the low nibble of the address
+	huc3AddrHigh = 0x5 // Set the high nibble of the address
+	huc3Extended = 0x6 // Run the extended command given by the argument
+)
+
+// HuC3 extended commands.
+const (
+	huc3Status = 0x2 // Respond with 1 to signal the RTC is ready
+	huc3Tone   = 0xE // Start the tone generator
+)
+
+// HuC3 is Hudson's mapper with a real-time clock, a tone generator and an infrared port.
+// The clock and tone generator are driven through a nibble-wide command interface:
+// registers 0x00-0x02 hold the minute of the day and 0x03-0x06 hold the day counter.
+// https://gbdev.io/pandocs/HuC3.html
+type HuC3 struct {
+	rom *gamepak.GamePak
+	ram []byte
+	ir  irPort
+
+	mode    uint8 // 0x0000-0x1FFF
+	romBank uint8 // 0x2000-0x3FFF - 7-bit ROM bank number, 0 is treated as 1
+	ramBank uint8 // 0x4000-0x5FFF - 2-bit RAM bank number
+
+	clock   Clock
+	last    time.Time // Time the clock was last brought up to date
+	minutes uint16    // Minute of the day, 0-1439
+	days    uint16
+
+	regs     [256]uint8 // Registers other than the clock, such as the alarm
+	address  uint8
+	command  uint8
+	response uint8
+	tone     bool
+}
+
+// NewHuC3 creates a HuC3 mapper. If the clock is nil, the system clock is used.
+func NewHuC3(gp *gamepak.GamePak, clock Clock) *HuC3 {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &HuC3{
+		rom:     gp,
+		ram:     newRAM(gp),
+		romBank: 1,
+		clock:   clock,
+		last:    clock.Now(),
+	}
+}
+
+// ConnectInfrared connects the cartridge's IR port to a peer.
+func (m *HuC3) ConnectInfrared(p InfraredPeer) {
+	m.ir.peer = p
+}
+
+// ToneActive reports whether the tone generator is playing.
+func (m *HuC3) ToneActive() bool {
+	return m.tone
+}
+
+func (m *HuC3) ReadROM(addr uint16) uint8 {
+	return m.rom.ReadOffset(m.ROMBank(addr)*romBankSize + uint(addr&0x3FFF))
+}
+
+// ROMBank returns the ROM bank mapped at addr.
+func (m *HuC3) ROMBank(addr uint16) uint {
+	if addr < 0x4000 {
+		return 0
+	}
+	return uint(m.romBank)
+}
+
+func (m *HuC3) WriteROM(addr uint16, data uint8) {
+	switch {
+	case addr < 0x2000:
+		m.mode = data & 0x0F
+	case addr < 0x4000:
+		m.romBank = data & 0x7F
+		if m.romBank == 0 {
+			m.romBank = 1
+		}
+	case addr < 0x6000:
+		m.ramBank = data & 0x03
+	}
+}
+
+func (m *HuC3) ReadRAM(addr uint16) uint8 {
+	switch m.mode {
+	case huc3RAM, huc3ReadOnlyRAM:
+		if len(m.ram) == 0 {
+			return 0xFF
+		}
+		return m.ram[m.ramOffset(addr)]
+	case huc3CommandRead:
+		return m.command<<4 | m.response
+	case huc3Semaphore:
+		// Bit 0 is set when the RTC is ready for another command, bit 1 while a tone plays
+		val := uint8(0x01)
+		if m.tone {
+			val |= 1 << 1
+		}
+		return val
+	case huc3Infrared:
+		return m.ir.read()
+	default:
+		return 0xFF
+	}
+}
+
+func (m *HuC3) WriteRAM(addr uint16, data uint8) {
+	switch m.mode {
+	case huc3RAM:
+		if len(m.ram) == 0 {
+			return
+		}
+		m.ram[m.ramOffset(addr)] = data
+	case huc3CommandWrite:
+		m.execute(data>>4&0x07, data&0x0F)
+	case huc3Infrared:
+		m.ir.write(data)
+	}
+}
+
+func (m *HuC3) ramOffset(addr uint16) int {
+	return (int(m.ramBank)*ramBankSize + int(addr)) % len(m.ram)
+}
+
+func (m *HuC3) execute(command, arg uint8) {
+	m.command = command
+
+	switch command {
+	case huc3Read:
+		m.response = m.readRegister(m.address)
+		m.address++
+	case huc3Set, huc3Write:
+		m.writeRegister(m.address, arg)
+		if command == huc3Write {
+			m.address++
+		}
+	case huc3AddrLow:
+		m.address = m.address&0xF0 | arg
+	case huc3AddrHigh:
+		m.address = m.address&0x0F | arg<<4
+	case huc3Extended:
+		// Any other command stops the tone generator
+		m.tone = arg == huc3Tone
+		if arg == huc3Status {
+			m.response = 0x01
+		}
+	}
+}
+
+func (m *HuC3) readRegister(reg uint8) uint8 {
+	switch {
+	case reg < 3:
+		m.update()
+		return uint8(m.minutes>>(reg*4)) & 0x0F
+	case reg < 7:
+		m.update()
+		return uint8(m.days>>((reg-3)*4)) & 0x0F
+	default:
+		return m.regs[reg]
+	}
+}
+
+func (m *HuC3) writeRegister(reg uint8, val uint8) {
+	switch {
+	case reg < 3:
+		m.update()
+		shift := reg * 4
+		m.minutes = m.minutes&^(0x0F<<shift) | uint16(val)<<shift
+	case reg < 7:
+		m.update()
+		shift := (reg - 3) * 4
+		m.days = m.days&^(0x0F<<shift) | uint16(val)<<shift
+	default:
+		m.regs[reg] = val
+	}
+}
+
+// update advances the clock by the whole minutes elapsed since the last update.
+func (m *HuC3) update() {
+	elapsed := int64(m.clock.Now().Sub(m.last) / time.Minute)
+	if elapsed <= 0 {
+		return
+	}
+	m.last = m.last.Add(time.Duration(elapsed) * time.Minute)
+
+	total := int64(m.minutes) + elapsed
+	m.minutes = uint16(total % (24 * 60))
+	m.days += uint16(total / (24 * 60))
+}
+
+// huc3FooterSize is the size of the clock state appended to save data.
+const huc3FooterSize = 16
+
+// SaveData returns the RAM followed by the clock: the minute of the day and day counter
+// as little-endian 32-bit values, then the 64-bit UNIX time they were last brought up to date.
+func (m *HuC3) SaveData() []byte {
+	m.update()
+
+	data := copyRAM(m.ram)
+	data = binary.LittleEndian.AppendUint32(data, uint32(m.minutes))
+	data = binary.LittleEndian.AppendUint32(data, uint32(m.days))
+	return binary.LittleEndian.AppendUint64(data, uint64(m.last.Unix()))
+}
+
+// LoadSaveData restores the RAM, and the clock if the save has one.
+func (m *HuC3) LoadSaveData(data []byte) {
+	copy(m.ram, data)
+	if len(data) != len(m.ram)+huc3FooterSize {
+		return
+	}
+
+	footer := data[len(m.ram):]
+	m.minutes = uint16(binary.LittleEndian.Uint32(footer[0:]) % (24 * 60))
+	m.days = uint16(binary.LittleEndian.Uint32(footer[4:]))
+
+	// Let the clock catch up on the time spent switched off
+	m.last = time.Unix(int64(binary.LittleEndian.Uint64(footer[8:])), 0)
+	m.update()
+}
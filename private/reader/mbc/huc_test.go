@@ -0,0 +1,178 @@
+package mbc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+func TestHuC1_Infrared(t *testing.T) {
+	a := NewHuC1(gamepak.NewGamePak(newTestROM(gamepak.HUC1_RAM_BATTERY, 64, 3)))
+	b := NewHuC1(gamepak.NewGamePak(newTestROM(gamepak.HUC1_RAM_BATTERY, 64, 3)))
+	peerA, peerB := NewInfraredLink()
+	a.ConnectInfrared(peerA)
+	b.ConnectInfrared(peerB)
+
+	// RAM is mapped outside of IR mode
+	a.WriteRAM(0x0000, 0x12)
+	if got := a.ReadRAM(0x0000); got != 0x12 {
+		t.Errorf("RAM: got 0x%02X, want 0x12", got)
+	}
+
+	a.WriteROM(0x0000, 0x0E)
+	b.WriteROM(0x0000, 0x0E)
+
+	if got := b.ReadRAM(0x0000); got != 0xC0 {
+		t.Errorf("no light: got 0x%02X, want 0xC0", got)
+	}
+	a.WriteRAM(0x0000, 0x01)
+	if got := b.ReadRAM(0x0000); got != 0xC1 {
+		t.Errorf("light: got 0x%02X, want 0xC1", got)
+	}
+	if got := a.ReadRAM(0x0000); got != 0xC0 {
+		t.Errorf("own LED seen: got 0x%02X, want 0xC0", got)
+	}
+	a.WriteRAM(0x0000, 0x00)
+	if got := b.ReadRAM(0x0000); got != 0xC0 {
+		t.Errorf("light off: got 0x%02X, want 0xC0", got)
+	}
+
+	// Leaving IR mode maps RAM back in
+	a.WriteROM(0x0000, 0x0A)
+	if got := a.ReadRAM(0x0000); got != 0x12 {
+		t.Errorf("RAM after IR mode: got 0x%02X, want 0x12", got)
+	}
+}
+
+// huc3Command writes a command in command mode and returns the response.
+func huc3Command(m *HuC3, command, arg uint8) uint8 {
+	m.WriteROM(0x0000, huc3CommandWrite)
+	m.WriteRAM(0x0000, command<<4|arg)
+	m.WriteROM(0x0000, huc3CommandRead)
+	return m.ReadRAM(0x0000) & 0x0F
+}
+
+func TestHuC3_RTC(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	m := NewHuC3(gamepak.NewGamePak(newTestROM(gamepak.HUC3, 64, 3)), clock)
+
+	clock.Advance(3*24*time.Hour + 10*time.Hour + 5*time.Minute + 59*time.Second)
+
+	huc3Command(m, huc3AddrLow, 0x0)
+	huc3Command(m, huc3AddrHigh, 0x0)
+	var minutes, days uint16
+	for i := 0; i < 3; i++ {
+		minutes |= uint16(huc3Command(m, huc3Read, 0)) << (i * 4)
+	}
+	for i := 0; i < 4; i++ {
+		days |= uint16(huc3Command(m, huc3Read, 0)) << (i * 4)
+	}
+
+	if minutes != 10*60+5 || days != 3 {
+		t.Errorf("got minute %d of day %d, want minute %d of day 3", minutes, days, 10*60+5)
+	}
+
+	// Set the clock to 23:59 and roll over into the next day
+	var lastMinute uint16 = 23*60 + 59
+	huc3Command(m, huc3AddrLow, 0x0)
+	for i := 0; i < 3; i++ {
+		huc3Command(m, huc3Write, uint8(lastMinute>>(i*4))&0x0F)
+	}
+	clock.Advance(2 * time.Minute)
+
+	huc3Command(m, huc3AddrLow, 0x0)
+	minutes = 0
+	for i := 0; i < 3; i++ {
+		minutes |= uint16(huc3Command(m, huc3Read, 0)) << (i * 4)
+	}
+	if got := huc3Command(m, huc3Read, 0); minutes != 1 || got != 4 {
+		t.Errorf("got minute %d of day %d, want minute 1 of day 4", minutes, got)
+	}
+}
+
+// readHuC3Clock reads the minute of the day and the day counter.
+func readHuC3Clock(m *HuC3) (minutes, days uint16) {
+	huc3Command(m, huc3AddrLow, 0x0)
+	huc3Command(m, huc3AddrHigh, 0x0)
+	for i := 0; i < 3; i++ {
+		minutes |= uint16(huc3Command(m, huc3Read, 0)) << (i * 4)
+	}
+	for i := 0; i < 4; i++ {
+		days |= uint16(huc3Command(m, huc3Read, 0)) << (i * 4)
+	}
+	return minutes, days
+}
+
+func TestHuC3_SaveData(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000_000, 0)}
+	rom := newTestROM(gamepak.HUC3, 64, 3)
+	m := NewHuC3(gamepak.NewGamePak(rom), clock)
+
+	m.WriteROM(0x0000, huc3RAM)
+	m.WriteRAM(0x0000, 0x42)
+	clock.Advance(2*24*time.Hour + 90*time.Minute)
+
+	data := m.SaveData()
+	if len(data) != 32*1024+huc3FooterSize {
+		t.Fatalf("got %d bytes of save data, want %d", len(data), 32*1024+huc3FooterSize)
+	}
+
+	// Power back on a day and an hour later
+	clock.Advance(25 * time.Hour)
+	restored := NewHuC3(gamepak.NewGamePak(rom), clock)
+	restored.LoadSaveData(data)
+
+	restored.WriteROM(0x0000, huc3RAM)
+	if got := restored.ReadRAM(0x0000); got != 0x42 {
+		t.Errorf("RAM: got 0x%02X, want 0x42", got)
+	}
+	if minutes, days := readHuC3Clock(restored); minutes != 150 || days != 3 {
+		t.Errorf("got minute %d of day %d, want minute 150 of day 3", minutes, days)
+	}
+
+	// Saves from before the clock was saved still load their RAM
+	old := NewHuC3(gamepak.NewGamePak(rom), clock)
+	old.LoadSaveData(data[:32*1024])
+	old.WriteROM(0x0000, huc3RAM)
+	if got := old.ReadRAM(0x0000); got != 0x42 {
+		t.Errorf("RAM without clock: got 0x%02X, want 0x42", got)
+	}
+	if minutes, days := readHuC3Clock(old); minutes != 0 || days != 0 {
+		t.Errorf("clock without a saved clock: got minute %d of day %d, want 0 of day 0", minutes, days)
+	}
+}
+
+func TestHuC3_Tone(t *testing.T) {
+	m := NewHuC3(gamepak.NewGamePak(newTestROM(gamepak.HUC3, 64, 3)), &fakeClock{})
+
+	if got := huc3Command(m, huc3Extended, huc3Status); got != 0x01 {
+		t.Errorf("status: got 0x%X, want 0x1", got)
+	}
+
+	huc3Command(m, huc3Extended, huc3Tone)
+	m.WriteROM(0x0000, huc3Semaphore)
+	if got := m.ReadRAM(0x0000); got != 0x03 || !m.ToneActive() {
+		t.Errorf("tone playing: got status 0x%02X, want 0x03", got)
+	}
+
+	huc3Command(m, huc3Extended, huc3Status)
+	m.WriteROM(0x0000, huc3Semaphore)
+	if got := m.ReadRAM(0x0000); got != 0x01 || m.ToneActive() {
+		t.Errorf("tone stopped: got status 0x%02X, want 0x01", got)
+	}
+}
+
+func TestHuC3_RAMModes(t *testing.T) {
+	m := NewHuC3(gamepak.NewGamePak(newTestROM(gamepak.HUC3, 64, 3)), &fakeClock{})
+
+	m.WriteROM(0x0000, huc3RAM)
+	m.WriteRAM(0x0000, 0x5A)
+
+	// Mode 0 maps RAM read-only
+	m.WriteROM(0x0000, huc3ReadOnlyRAM)
+	m.WriteRAM(0x0000, 0x00)
+	if got := m.ReadRAM(0x0000); got != 0x5A {
+		t.Errorf("got 0x%02X, want 0x5A", got)
+	}
+}
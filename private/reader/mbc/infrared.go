@@ -0,0 +1,63 @@
+package mbc
+
+import "sync/atomic"
+
+// InfraredPeer is whatever sits across from a cartridge's infrared port,
+// such as another cartridge or a remote control.
+type InfraredPeer interface {
+	// Sense reports whether the peer's LED is currently lit.
+	Sense() bool
+	// Emit is called whenever the cartridge's own LED turns on or off.
+	Emit(on bool)
+}
+
+// Infrared is implemented by mappers with an infrared port.
+type Infrared interface {
+	ConnectInfrared(p InfraredPeer)
+}
+
+// NewInfraredLink returns two peers facing each other, so that two
+// cartridges can talk over infrared in-process.
+func NewInfraredLink() (a, b InfraredPeer) {
+	link := &infraredLink{}
+	return &infraredPort{link: link, side: 0}, &infraredPort{link: link, side: 1}
+}
+
+type infraredLink struct {
+	leds [2]atomic.Bool
+}
+
+type infraredPort struct {
+	link *infraredLink
+	side int
+}
+
+// Sense reports whether the other end's LED is lit.
+func (p *infraredPort) Sense() bool {
+	return p.link.leds[1-p.side].Load()
+}
+
+// Emit sets this end's LED.
+func (p *infraredPort) Emit(on bool) {
+	p.link.leds[p.side].Store(on)
+}
+
+// irPort is the infrared register found at 0xA000 on Hudson mappers when IR mode is selected.
+type irPort struct {
+	peer InfraredPeer
+}
+
+// read returns 0xC1 if light is received, 0xC0 otherwise.
+func (p *irPort) read() uint8 {
+	if p.peer != nil && p.peer.Sense() {
+		return 0xC1
+	}
+	return 0xC0
+}
+
+// write turns the LED on or off with bit 0.
+func (p *irPort) write(data uint8) {
+	if p.peer != nil {
+		p.peer.Emit(data&0x01 != 0)
+	}
+}
@@ -0,0 +1,76 @@
+package mbc
+
+import "github.com/colecrouter/gameboy-go/private/reader/gamepak"
+
+const (
+	romBankSize = 0x4000 // 16 KiB, switchable at 0x4000-0x7FFF
+	ramBankSize = 0x2000 // 8 KiB, switchable at 0xA000-0xBFFF
+)
+
+// MBC is a memory bank controller sitting between the bus and the cartridge.
+// ROM addresses are relative to 0x0000, RAM addresses are relative to 0xA000.
+type MBC interface {
+	ReadROM(addr uint16) uint8
+	WriteROM(addr uint16, data uint8)
+	ReadRAM(addr uint16) uint8
+	WriteRAM(addr uint16, data uint8)
+}
+
+// Banked is implemented by mappers that can report which ROM bank is mapped where, for debugging tools.
+type Banked interface {
+	ROMBank(addr uint16) uint
+}
+
+// Battery is implemented by mappers with RAM that can be kept alive by a battery.
+// The returned data is a copy and may include extra state such as a real-time clock.
+type Battery interface {
+	SaveData() []byte
+	LoadSaveData(data []byte)
+}
+
+// Camera is implemented by mappers with an image sensor.
+type Camera interface {
+	ConnectCamera(src ImageSource)
+}
+
+// Rumbler is implemented by mappers that drive a rumble motor.
+type Rumbler interface {
+	OnRumble(f func(on bool))
+}
+
+// New returns the mapper declared by the cartridge header.
+// Unsupported cartridge types fall back to a plain 32 KiB ROM.
+func New(gp *gamepak.GamePak) MBC {
+	switch gp.CartridgeType() {
+	case gamepak.MBC1, gamepak.MBC1_RAM, gamepak.MBC1_RAM_BATTERY:
+		return NewMBC1(gp)
+	case gamepak.MBC2, gamepak.MBC2_BATTERY:
+		return NewMBC2(gp)
+	case gamepak.MBC3, gamepak.MBC3_RAM, gamepak.MBC3_RAM_BATTERY,
+		gamepak.MBC3_TIMER_BATTERY, gamepak.MBC3_TIMER_RAM_BATTERY:
+		return NewMBC3(gp, nil)
+	case gamepak.MBC5, gamepak.MBC5_RAM, gamepak.MBC5_RAM_BATTERY,
+		gamepak.MBC5_RUMBLE, gamepak.MBC5_RUMBLE_RAM, gamepak.MBC5_RUMBLE_RAM_BATTERY:
+		return NewMBC5(gp)
+	case gamepak.MBC7_SENSOR_RUMBLE_RAM_BATTERY:
+		return NewMBC7(gp)
+	case gamepak.POCKET_CAMERA:
+		return NewPocketCamera(gp)
+	case gamepak.HUC1_RAM_BATTERY:
+		return NewHuC1(gp)
+	case gamepak.HUC3:
+		return NewHuC3(gp, nil)
+	default:
+		return NewROMOnly(gp)
+	}
+}
+
+// newRAM allocates the external RAM declared by the cartridge header.
+func newRAM(gp *gamepak.GamePak) []byte {
+	return make([]byte, gp.RamSize())
+}
+
+// copyRAM returns a copy of the cartridge RAM.
+func copyRAM(ram []byte) []byte {
+	return append([]byte(nil), ram...)
+}
@@ -0,0 +1,120 @@
+package mbc
+
+import "github.com/colecrouter/gameboy-go/private/reader/gamepak"
+
+// MBC1 supports up to 2 MiB of ROM and 32 KiB of RAM.
+// https://gbdev.io/pandocs/MBC1.html
+type MBC1 struct {
+	rom *gamepak.GamePak
+	ram []byte
+
+	ramEnabled bool  // 0x0000-0x1FFF
+	bank1      uint8 // 0x2000-0x3FFF - 5-bit ROM bank number, 0 is treated as 1
+	bank2      uint8 // 0x4000-0x5FFF - 2-bit upper ROM bank or RAM bank number
+	advanced   bool  // 0x6000-0x7FFF - Banking mode select
+
+	// MBC1M multicarts wire BANK2 to ROM address lines 18-19 instead of 19-20,
+	// so only the low 4 bits of BANK1 reach the ROM.
+	multicart bool
+}
+
+func NewMBC1(gp *gamepak.GamePak) *MBC1 {
+	return &MBC1{
+		rom:       gp,
+		ram:       newRAM(gp),
+		bank1:     1,
+		multicart: isMulticart(gp),
+	}
+}
+
+func (m *MBC1) ReadROM(addr uint16) uint8 {
+	return m.rom.ReadOffset(m.ROMBank(addr)*romBankSize + uint(addr&0x3FFF))
+}
+
+// ROMBank returns the ROM bank mapped at addr.
+func (m *MBC1) ROMBank(addr uint16) uint {
+	if addr < 0x4000 {
+		// Mode 1 lets BANK2 affect the fixed area too
+		if m.advanced {
+			return m.upperBank()
+		}
+		return 0
+	}
+	return m.upperBank() | m.lowerBank()
+}
+
+func (m *MBC1) WriteROM(addr uint16, data uint8) {
+	switch {
+	case addr < 0x2000:
+		m.ramEnabled = data&0x0F == 0x0A
+	case addr < 0x4000:
+		// The zero check happens on the full 5 bits, even on multicarts
+		m.bank1 = data & 0x1F
+		if m.bank1 == 0 {
+			m.bank1 = 1
+		}
+	case addr < 0x6000:
+		m.bank2 = data & 0x03
+	default:
+		m.advanced = data&0x01 != 0
+	}
+}
+
+func (m *MBC1) ReadRAM(addr uint16) uint8 {
+	if !m.ramEnabled || len(m.ram) == 0 {
+		return 0xFF
+	}
+	return m.ram[m.ramOffset(addr)]
+}
+
+func (m *MBC1) WriteRAM(addr uint16, data uint8) {
+	if !m.ramEnabled || len(m.ram) == 0 {
+		return
+	}
+	m.ram[m.ramOffset(addr)] = data
+}
+
+func (m *MBC1) ramOffset(addr uint16) int {
+	var bank int
+	if m.advanced {
+		bank = int(m.bank2)
+	}
+	return (bank*ramBankSize + int(addr)) % len(m.ram)
+}
+
+func (m *MBC1) upperBank() uint {
+	if m.multicart {
+		return uint(m.bank2) << 4
+	}
+	return uint(m.bank2) << 5
+}
+
+func (m *MBC1) lowerBank() uint {
+	if m.multicart {
+		return uint(m.bank1 & 0x0F)
+	}
+	return uint(m.bank1)
+}
+
+// isMulticart detects MBC1M carts, which are 8 Mbit and carry a second
+// Nintendo logo at the start of the game in bank 0x10.
+func isMulticart(gp *gamepak.GamePak) bool {
+	if gp.Size() != 64*romBankSize {
+		return false
+	}
+	base := uint(0x10*romBankSize + 0x0104)
+	for i, b := range gamepak.NintendoLogo {
+		if gp.ReadOffset(base+uint(i)) != b {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *MBC1) SaveData() []byte {
+	return copyRAM(m.ram)
+}
+
+func (m *MBC1) LoadSaveData(data []byte) {
+	copy(m.ram, data)
+}
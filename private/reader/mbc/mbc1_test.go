@@ -0,0 +1,157 @@
+package mbc
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+// newTestROM builds a ROM image whose banks each start with their own bank number.
+func newTestROM(cartType gamepak.CartridgeType, banks int, ramCode uint8) []byte {
+	rom := make([]byte, banks*romBankSize)
+	for bank := 0; bank < banks; bank++ {
+		rom[bank*romBankSize] = uint8(bank)
+		rom[bank*romBankSize+1] = uint8(bank >> 8)
+	}
+	rom[0x147] = uint8(cartType)
+	for size := 2; size < banks; size <<= 1 {
+		rom[0x148]++
+	}
+	rom[0x149] = ramCode
+	return rom
+}
+
+func TestMBC1_ROMBanking(t *testing.T) {
+	m := NewMBC1(gamepak.NewGamePak(newTestROM(gamepak.MBC1, 128, 0)))
+
+	tests := []struct {
+		name      string
+		bank1     uint8
+		bank2     uint8
+		advanced  bool
+		wantLower uint8
+		wantUpper uint8
+	}{
+		{"Bank 0 maps to 1", 0x00, 0x00, false, 0x00, 0x01},
+		{"Bank 5", 0x05, 0x00, false, 0x00, 0x05},
+		{"Bank 0x20 maps to 0x21", 0x00, 0x01, false, 0x00, 0x21},
+		{"Upper bits ignored", 0xE3, 0x02, false, 0x00, 0x43},
+		{"Mode 1 switches the fixed area", 0x01, 0x03, true, 0x60, 0x61},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m.WriteROM(0x2000, tc.bank1)
+			m.WriteROM(0x4000, tc.bank2)
+			if tc.advanced {
+				m.WriteROM(0x6000, 0x01)
+			} else {
+				m.WriteROM(0x6000, 0x00)
+			}
+
+			if got := m.ReadROM(0x0000); got != tc.wantLower {
+				t.Errorf("0x0000: got bank 0x%02X, want 0x%02X", got, tc.wantLower)
+			}
+			if got := m.ReadROM(0x4000); got != tc.wantUpper {
+				t.Errorf("0x4000: got bank 0x%02X, want 0x%02X", got, tc.wantUpper)
+			}
+		})
+	}
+}
+
+func TestMBC1_ROMBankWrapsToSize(t *testing.T) {
+	m := NewMBC1(gamepak.NewGamePak(newTestROM(gamepak.MBC1, 8, 0)))
+
+	m.WriteROM(0x2000, 0x0B) // Bank 11 on an 8 bank ROM
+	if got := m.ReadROM(0x4000); got != 0x03 {
+		t.Errorf("got bank 0x%02X, want 0x03", got)
+	}
+}
+
+func TestMBC1_RAM(t *testing.T) {
+	m := NewMBC1(gamepak.NewGamePak(newTestROM(gamepak.MBC1_RAM, 4, 3))) // 32 KiB RAM
+
+	// RAM is disabled at power on
+	m.WriteRAM(0x0000, 0x12)
+	if got := m.ReadRAM(0x0000); got != 0xFF {
+		t.Errorf("disabled RAM: got 0x%02X, want 0xFF", got)
+	}
+
+	m.WriteROM(0x0000, 0x0A)
+	m.WriteRAM(0x0000, 0x12)
+
+	// Bank 2 only selects RAM in mode 1
+	m.WriteROM(0x4000, 0x02)
+	if got := m.ReadRAM(0x0000); got != 0x12 {
+		t.Errorf("mode 0: got 0x%02X, want 0x12", got)
+	}
+
+	m.WriteROM(0x6000, 0x01)
+	m.WriteRAM(0x0000, 0x34)
+	m.WriteROM(0x6000, 0x00)
+	if got := m.ReadRAM(0x0000); got != 0x12 {
+		t.Errorf("bank 0 overwritten: got 0x%02X, want 0x12", got)
+	}
+	m.WriteROM(0x6000, 0x01)
+	if got := m.ReadRAM(0x0000); got != 0x34 {
+		t.Errorf("bank 2: got 0x%02X, want 0x34", got)
+	}
+
+	// Anything other than 0x0A in the low nibble disables RAM
+	m.WriteROM(0x0000, 0x1B)
+	if got := m.ReadRAM(0x0000); got != 0xFF {
+		t.Errorf("disabled RAM: got 0x%02X, want 0xFF", got)
+	}
+}
+
+func TestMBC1_Multicart(t *testing.T) {
+	rom := newTestROM(gamepak.MBC1, 64, 0)
+	copy(rom[0x10*romBankSize+0x104:], gamepak.NintendoLogo[:])
+	m := NewMBC1(gamepak.NewGamePak(rom))
+
+	if !m.multicart {
+		t.Fatal("expected MBC1M multicart to be detected")
+	}
+
+	// BANK2 selects the game in 256 KiB steps
+	m.WriteROM(0x4000, 0x01)
+	m.WriteROM(0x2000, 0x02)
+	if got := m.ReadROM(0x4000); got != 0x12 {
+		t.Errorf("got bank 0x%02X, want 0x12", got)
+	}
+
+	// Bit 4 of BANK1 is not connected
+	m.WriteROM(0x2000, 0x10)
+	if got := m.ReadROM(0x4000); got != 0x10 {
+		t.Errorf("got bank 0x%02X, want 0x10", got)
+	}
+
+	m.WriteROM(0x6000, 0x01)
+	if got := m.ReadROM(0x0000); got != 0x10 {
+		t.Errorf("mode 1 fixed area: got bank 0x%02X, want 0x10", got)
+	}
+}
+
+func TestBanked(t *testing.T) {
+	types := []gamepak.CartridgeType{
+		gamepak.ROM_ONLY, gamepak.MBC1, gamepak.MBC2, gamepak.MBC3, gamepak.MBC5,
+		gamepak.MBC7_SENSOR_RUMBLE_RAM_BATTERY, gamepak.POCKET_CAMERA, gamepak.HUC1_RAM_BATTERY, gamepak.HUC3,
+	}
+
+	for _, cartType := range types {
+		t.Run(cartType.String(), func(t *testing.T) {
+			m := New(gamepak.NewGamePak(newTestROM(cartType, 8, 0)))
+			b, ok := m.(Banked)
+			if !ok {
+				t.Fatalf("%T doesn't report its banks", m)
+			}
+
+			// The reported bank must be the one ReadROM reads from
+			for _, addr := range []uint16{0x0000, 0x4000} {
+				if got := uint(m.ReadROM(addr)); got != b.ROMBank(addr) {
+					t.Errorf("ROMBank(%04X) = %d, but ReadROM read bank %d", addr, b.ROMBank(addr), got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package mbc
+
+import "github.com/colecrouter/gameboy-go/private/reader/gamepak"
+
+// MBC2 supports up to 256 KiB of ROM and has 512 half-bytes of RAM built in.
+// https://gbdev.io/pandocs/MBC2.html
+type MBC2 struct {
+	rom *gamepak.GamePak
+	ram [512]uint8 // Only the lower 4 bits of each byte are used
+
+	ramEnabled bool  // 0x0000-0x3FFF, address bit 8 clear
+	romBank    uint8 // 0x0000-0x3FFF, address bit 8 set - 4-bit ROM bank number, 0 is treated as 1
+}
+
+func NewMBC2(gp *gamepak.GamePak) *MBC2 {
+	return &MBC2{rom: gp, romBank: 1}
+}
+
+func (m *MBC2) ReadROM(addr uint16) uint8 {
+	return m.rom.ReadOffset(m.ROMBank(addr)*romBankSize + uint(addr&0x3FFF))
+}
+
+// ROMBank returns the ROM bank mapped at addr.
+func (m *MBC2) ROMBank(addr uint16) uint {
+	if addr < 0x4000 {
+		return 0
+	}
+	return uint(m.romBank)
+}
+
+func (m *MBC2) WriteROM(addr uint16, data uint8) {
+	if addr >= 0x4000 {
+		return
+	}
+
+	// Bit 8 of the address selects which register is written
+	if addr&0x0100 == 0 {
+		m.ramEnabled = data&0x0F == 0x0A
+	} else {
+		m.romBank = data & 0x0F
+		if m.romBank == 0 {
+			m.romBank = 1
+		}
+	}
+}
+
+// ReadRAM reads a half-byte of RAM. The RAM is mirrored across the whole
+// 0xA000-0xBFFF window, and the unconnected upper 4 bits read as 1.
+func (m *MBC2) ReadRAM(addr uint16) uint8 {
+	if !m.ramEnabled {
+		return 0xFF
+	}
+	return 0xF0 | m.ram[addr&0x01FF]
+}
+
+func (m *MBC2) WriteRAM(addr uint16, data uint8) {
+	if !m.ramEnabled {
+		return
+	}
+	m.ram[addr&0x01FF] = data & 0x0F
+}
+
+func (m *MBC2) SaveData() []byte {
+	return copyRAM(m.ram[:])
+}
+
+func (m *MBC2) LoadSaveData(data []byte) {
+	for i := 0; i < len(m.ram) && i < len(data); i++ {
+		m.ram[i] = data[i] & 0x0F
+	}
+}
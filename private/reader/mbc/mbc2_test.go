@@ -0,0 +1,55 @@
+package mbc
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+func TestMBC2_RegisterSelect(t *testing.T) {
+	m := NewMBC2(gamepak.NewGamePak(newTestROM(gamepak.MBC2, 16, 0)))
+
+	// Address bit 8 set selects the ROM bank
+	m.WriteROM(0x2100, 0x05)
+	if got := m.ReadROM(0x4000); got != 0x05 {
+		t.Errorf("got bank 0x%02X, want 0x05", got)
+	}
+	m.WriteROM(0x0100, 0x00)
+	if got := m.ReadROM(0x4000); got != 0x01 {
+		t.Errorf("bank 0: got bank 0x%02X, want 0x01", got)
+	}
+
+	// Address bit 8 clear enables RAM, even if it looks like a ROM bank write
+	m.WriteROM(0x3E00, 0x0A)
+	if got := m.ReadROM(0x4000); got != 0x01 {
+		t.Errorf("RAM enable changed bank: got 0x%02X, want 0x01", got)
+	}
+	m.WriteRAM(0x0000, 0x05)
+	if got := m.ReadRAM(0x0000); got != 0xF5 {
+		t.Errorf("got 0x%02X, want 0xF5", got)
+	}
+}
+
+func TestMBC2_RAM(t *testing.T) {
+	m := NewMBC2(gamepak.NewGamePak(newTestROM(gamepak.MBC2_BATTERY, 16, 0)))
+
+	m.WriteRAM(0x0000, 0x0C)
+	if got := m.ReadRAM(0x0000); got != 0xFF {
+		t.Errorf("disabled RAM: got 0x%02X, want 0xFF", got)
+	}
+
+	m.WriteROM(0x0000, 0x0A)
+
+	// Only the low nibble is stored
+	m.WriteRAM(0x0010, 0xAB)
+	if got := m.ReadRAM(0x0010); got != 0xFB {
+		t.Errorf("got 0x%02X, want 0xFB", got)
+	}
+
+	// 512 half-bytes are mirrored across the window
+	for _, mirror := range []uint16{0x0210, 0x0410, 0x1E10} {
+		if got := m.ReadRAM(mirror); got != 0xFB {
+			t.Errorf("mirror 0x%04X: got 0x%02X, want 0xFB", 0xA000+mirror, got)
+		}
+	}
+}
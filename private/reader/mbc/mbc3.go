@@ -0,0 +1,265 @@
+package mbc
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+// MBC3 supports up to 2 MiB of ROM, 32 KiB of RAM and an optional real-time clock.
+// https://gbdev.io/pandocs/MBC3.html
+type MBC3 struct {
+	rom *gamepak.GamePak
+	ram []byte
+
+	ramEnabled bool  // 0x0000-0x1FFF - Also enables the RTC registers
+	romBank    uint8 // 0x2000-0x3FFF - 7-bit ROM bank number, 0 is treated as 1
+	ramSelect  uint8 // 0x4000-0x5FFF - RAM bank 0x00-0x03 or RTC register 0x08-0x0C
+	latchState uint8 // 0x6000-0x7FFF - Last value written, latching happens on 0x00 -> 0x01
+
+	rtc *rtc
+}
+
+// NewMBC3 creates an MBC3 mapper. The clock only matters for cartridges with a timer;
+// if it is nil, the system clock is used.
+func NewMBC3(gp *gamepak.GamePak, clock Clock) *MBC3 {
+	m := &MBC3{
+		rom:        gp,
+		ram:        newRAM(gp),
+		romBank:    1,
+		latchState: 0xFF,
+	}
+
+	switch gp.CartridgeType() {
+	case gamepak.MBC3_TIMER_BATTERY, gamepak.MBC3_TIMER_RAM_BATTERY:
+		if clock == nil {
+			clock = systemClock{}
+		}
+		m.rtc = newRTC(clock)
+	}
+
+	return m
+}
+
+func (m *MBC3) ReadROM(addr uint16) uint8 {
+	return m.rom.ReadOffset(m.ROMBank(addr)*romBankSize + uint(addr&0x3FFF))
+}
+
+// ROMBank returns the ROM bank mapped at addr.
+func (m *MBC3) ROMBank(addr uint16) uint {
+	if addr < 0x4000 {
+		return 0
+	}
+	return uint(m.romBank)
+}
+
+func (m *MBC3) WriteROM(addr uint16, data uint8) {
+	switch {
+	case addr < 0x2000:
+		m.ramEnabled = data&0x0F == 0x0A
+	case addr < 0x4000:
+		m.romBank = data & 0x7F
+		if m.romBank == 0 {
+			m.romBank = 1
+		}
+	case addr < 0x6000:
+		m.ramSelect = data & 0x0F
+	default:
+		if m.rtc != nil && m.latchState == 0x00 && data == 0x01 {
+			m.rtc.latch()
+		}
+		m.latchState = data
+	}
+}
+
+func (m *MBC3) ReadRAM(addr uint16) uint8 {
+	if !m.ramEnabled {
+		return 0xFF
+	}
+
+	switch {
+	case m.ramSelect <= 0x03:
+		if len(m.ram) == 0 {
+			return 0xFF
+		}
+		return m.ram[m.ramOffset(addr)]
+	case m.ramSelect >= 0x08 && m.ramSelect <= 0x0C && m.rtc != nil:
+		return m.rtc.read(m.ramSelect - 0x08)
+	default:
+		return 0xFF
+	}
+}
+
+func (m *MBC3) WriteRAM(addr uint16, data uint8) {
+	if !m.ramEnabled {
+		return
+	}
+
+	switch {
+	case m.ramSelect <= 0x03:
+		if len(m.ram) == 0 {
+			return
+		}
+		m.ram[m.ramOffset(addr)] = data
+	case m.ramSelect >= 0x08 && m.ramSelect <= 0x0C && m.rtc != nil:
+		m.rtc.write(m.ramSelect-0x08, data)
+	}
+}
+
+// SaveData returns the cartridge RAM, followed by the clock state on cartridges with a timer.
+// The clock is stored in the common 48-byte footer format: the live and latched registers as
+// ten little-endian 32-bit values, then the 64-bit UNIX time they were last brought up to date.
+func (m *MBC3) SaveData() []byte {
+	data := copyRAM(m.ram)
+	if m.rtc == nil {
+		return data
+	}
+
+	live := m.rtc.registers()
+	for _, regs := range [][5]uint8{live, m.rtc.latched} {
+		for _, reg := range regs {
+			data = binary.LittleEndian.AppendUint32(data, uint32(reg))
+		}
+	}
+	return binary.LittleEndian.AppendUint64(data, uint64(m.rtc.last.Unix()))
+}
+
+func (m *MBC3) LoadSaveData(data []byte) {
+	copy(m.ram, data)
+	if m.rtc == nil || len(data) != len(m.ram)+rtcFooterSize {
+		return
+	}
+
+	footer := data[len(m.ram):]
+	var regs [10]uint8
+	for i := range regs {
+		regs[i] = uint8(binary.LittleEndian.Uint32(footer[i*4:]))
+	}
+	for i, val := range regs[:5] {
+		m.rtc.write(uint8(i), val)
+	}
+	copy(m.rtc.latched[:], regs[5:])
+
+	// Let the clock catch up on the time spent switched off
+	m.rtc.last = time.Unix(int64(binary.LittleEndian.Uint64(footer[40:])), 0)
+	m.rtc.update()
+}
+
+func (m *MBC3) ramOffset(addr uint16) int {
+	return (int(m.ramSelect)*ramBankSize + int(addr)) % len(m.ram)
+}
+
+// rtcFooterSize is the size of the clock state appended to save data.
+const rtcFooterSize = 48
+
+// RTC register indexes, as selected by writing 0x08-0x0C to 0x4000-0x5FFF.
+const (
+	rtcSeconds = iota
+	rtcMinutes
+	rtcHours
+	rtcDaysLow
+	rtcDaysHigh
+)
+
+// rtc is the MBC3 real-time clock. The live counters only advance when they are
+// observed; the elapsed time since the last observation is applied in one go.
+type rtc struct {
+	clock Clock
+	last  time.Time // Time the live counters were last brought up to date
+
+	seconds uint8  // 0-59
+	minutes uint8  // 0-59
+	hours   uint8  // 0-23
+	days    uint16 // 0-511
+	halted  bool   // DH bit 6
+	carry   bool   // DH bit 7 - Set when the day counter overflows, until cleared
+
+	latched [5]uint8
+}
+
+func newRTC(clock Clock) *rtc {
+	return &rtc{clock: clock, last: clock.Now()}
+}
+
+// update advances the live counters by the whole seconds elapsed since the last update.
+func (r *rtc) update() {
+	now := r.clock.Now()
+	if r.halted {
+		r.last = now
+		return
+	}
+
+	elapsed := int64(now.Sub(r.last) / time.Second)
+	if elapsed <= 0 {
+		return
+	}
+	r.last = r.last.Add(time.Duration(elapsed) * time.Second)
+
+	total := int64(r.seconds) + elapsed
+	r.seconds = uint8(total % 60)
+	total = total/60 + int64(r.minutes)
+	r.minutes = uint8(total % 60)
+	total = total/60 + int64(r.hours)
+	r.hours = uint8(total % 24)
+	total = total/24 + int64(r.days)
+	if total > 0x1FF {
+		r.carry = true
+	}
+	r.days = uint16(total & 0x1FF)
+}
+
+// latch copies the live counters into the registers visible to the CPU.
+func (r *rtc) latch() {
+	r.update()
+	r.latched = r.registers()
+}
+
+func (r *rtc) registers() [5]uint8 {
+	return [5]uint8{
+		rtcSeconds:  r.seconds,
+		rtcMinutes:  r.minutes,
+		rtcHours:    r.hours,
+		rtcDaysLow:  uint8(r.days),
+		rtcDaysHigh: r.daysHigh(),
+	}
+}
+
+func (r *rtc) daysHigh() uint8 {
+	val := uint8(r.days>>8) & 0x01
+	if r.halted {
+		val |= 1 << 6
+	}
+	if r.carry {
+		val |= 1 << 7
+	}
+	return val
+}
+
+func (r *rtc) read(reg uint8) uint8 {
+	return r.latched[reg]
+}
+
+func (r *rtc) write(reg uint8, data uint8) {
+	r.update()
+
+	switch reg {
+	case rtcSeconds:
+		r.seconds = data & 0x3F
+		// Writing the seconds resets the sub-second counter
+		r.last = r.clock.Now()
+	case rtcMinutes:
+		r.minutes = data & 0x3F
+	case rtcHours:
+		r.hours = data & 0x1F
+	case rtcDaysLow:
+		r.days = r.days&0x100 | uint16(data)
+	case rtcDaysHigh:
+		r.days = r.days&0xFF | uint16(data&0x01)<<8
+		r.halted = data&(1<<6) != 0
+		r.carry = data&(1<<7) != 0
+	}
+
+	// Writes are visible to the CPU straight away
+	r.latched[reg] = r.registers()[reg]
+}
@@ -0,0 +1,180 @@
+package mbc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestMBC3(clock Clock) *MBC3 {
+	m := NewMBC3(gamepak.NewGamePak(newTestROM(gamepak.MBC3_TIMER_RAM_BATTERY, 128, 3)), clock)
+	m.WriteROM(0x0000, 0x0A)
+	return m
+}
+
+// readRTC latches the clock and reads all five RTC registers.
+func readRTC(m *MBC3) [5]uint8 {
+	m.WriteROM(0x6000, 0x00)
+	m.WriteROM(0x6000, 0x01)
+
+	var regs [5]uint8
+	for i := range regs {
+		m.WriteROM(0x4000, 0x08+uint8(i))
+		regs[i] = m.ReadRAM(0)
+	}
+	return regs
+}
+
+func TestMBC3_ROMBanking(t *testing.T) {
+	m := newTestMBC3(&fakeClock{})
+
+	for _, bank := range []uint8{0x01, 0x02, 0x40, 0x7F} {
+		m.WriteROM(0x2000, bank)
+		if got := m.ReadROM(0x4000); got != bank {
+			t.Errorf("got bank 0x%02X, want 0x%02X", got, bank)
+		}
+	}
+
+	m.WriteROM(0x2000, 0x00)
+	if got := m.ReadROM(0x4000); got != 0x01 {
+		t.Errorf("bank 0: got bank 0x%02X, want 0x01", got)
+	}
+}
+
+func TestMBC3_RAMBanking(t *testing.T) {
+	m := newTestMBC3(&fakeClock{})
+
+	for bank := uint8(0); bank < 4; bank++ {
+		m.WriteROM(0x4000, bank)
+		m.WriteRAM(0x1FFF, 0x10+bank)
+	}
+	for bank := uint8(0); bank < 4; bank++ {
+		m.WriteROM(0x4000, bank)
+		if got := m.ReadRAM(0x1FFF); got != 0x10+bank {
+			t.Errorf("bank %d: got 0x%02X, want 0x%02X", bank, got, 0x10+bank)
+		}
+	}
+}
+
+func TestMBC3_RTCLatch(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	m := newTestMBC3(clock)
+
+	clock.Advance(1*time.Hour + 2*time.Minute + 3*time.Second)
+	got := readRTC(m)
+	want := [5]uint8{3, 2, 1, 0, 0}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Registers hold their value until latched again
+	clock.Advance(10 * time.Second)
+	m.WriteROM(0x4000, 0x08)
+	if sec := m.ReadRAM(0); sec != 3 {
+		t.Errorf("unlatched seconds: got %d, want 3", sec)
+	}
+
+	// Latching needs a 0x00 -> 0x01 sequence
+	m.WriteROM(0x6000, 0x01)
+	if sec := m.ReadRAM(0); sec != 3 {
+		t.Errorf("seconds after incomplete latch: got %d, want 3", sec)
+	}
+}
+
+func TestMBC3_RTCDayCarry(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	m := newTestMBC3(clock)
+
+	clock.Advance(300 * 24 * time.Hour)
+	got := readRTC(m)
+	if got[rtcDaysLow] != uint8(300&0xFF) || got[rtcDaysHigh] != 0x01 {
+		t.Errorf("day 300: got DL=0x%02X DH=0x%02X", got[rtcDaysLow], got[rtcDaysHigh])
+	}
+
+	clock.Advance(215 * 24 * time.Hour)
+	got = readRTC(m)
+	if got[rtcDaysLow] != 3 || got[rtcDaysHigh] != 0x80 {
+		t.Errorf("day 515: got DL=0x%02X DH=0x%02X, want DL=0x03 DH=0x80", got[rtcDaysLow], got[rtcDaysHigh])
+	}
+
+	// The carry bit stays set until cleared by software
+	m.WriteROM(0x4000, 0x0C)
+	m.WriteRAM(0, 0x00)
+	got = readRTC(m)
+	if got[rtcDaysHigh] != 0x00 {
+		t.Errorf("cleared carry: got DH=0x%02X, want 0x00", got[rtcDaysHigh])
+	}
+}
+
+func TestMBC3_RTCHalt(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	m := newTestMBC3(clock)
+
+	// Halt and set the time to 23:59:50
+	m.WriteROM(0x4000, 0x0C)
+	m.WriteRAM(0, 0x40)
+	for reg, val := range []uint8{50, 59, 23} {
+		m.WriteROM(0x4000, 0x08+uint8(reg))
+		m.WriteRAM(0, val)
+	}
+
+	clock.Advance(1 * time.Hour)
+	if got := readRTC(m); got != [5]uint8{50, 59, 23, 0, 0x40} {
+		t.Errorf("halted: got %v", got)
+	}
+
+	// Resume
+	m.WriteROM(0x4000, 0x0C)
+	m.WriteRAM(0, 0x00)
+	clock.Advance(15 * time.Second)
+	if got := readRTC(m); got != [5]uint8{5, 0, 0, 1, 0} {
+		t.Errorf("resumed: got %v", got)
+	}
+}
+
+func TestMBC3_NoTimer(t *testing.T) {
+	m := NewMBC3(gamepak.NewGamePak(newTestROM(gamepak.MBC3_RAM, 8, 2)), nil)
+	m.WriteROM(0x0000, 0x0A)
+	m.WriteROM(0x4000, 0x08)
+	if got := m.ReadRAM(0); got != 0xFF {
+		t.Errorf("got 0x%02X, want 0xFF", got)
+	}
+}
+
+func TestMBC3_SaveData(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000_000, 0)}
+	m := newTestMBC3(clock)
+	m.WriteRAM(0x0000, 0x42)
+	clock.Advance(90 * time.Second)
+	readRTC(m)
+
+	data := m.SaveData()
+	if len(data) != 32*1024+rtcFooterSize {
+		t.Fatalf("got %d bytes of save data, want %d", len(data), 32*1024+rtcFooterSize)
+	}
+
+	// Power back on an hour later
+	clock.Advance(1 * time.Hour)
+	restored := newTestMBC3(clock)
+	restored.LoadSaveData(data)
+
+	if got := restored.ReadRAM(0x0000); got != 0x42 {
+		t.Errorf("RAM: got 0x%02X, want 0x42", got)
+	}
+	if got := readRTC(restored); got != [5]uint8{30, 1, 1, 0, 0} {
+		t.Errorf("RTC: got %v, want 01:01:30", got)
+	}
+}
@@ -0,0 +1,105 @@
+package mbc
+
+import "github.com/colecrouter/gameboy-go/private/reader/gamepak"
+
+// MBC5 supports up to 8 MiB of ROM and 128 KiB of RAM.
+// Rumble cartridges use bit 3 of the RAM bank register to drive the motor.
+// https://gbdev.io/pandocs/MBC5.html
+type MBC5 struct {
+	rom *gamepak.GamePak
+	ram []byte
+
+	ramEnabled bool   // 0x0000-0x1FFF
+	romBank    uint16 // 0x2000-0x3FFF - 9-bit ROM bank number, 0 is allowed
+	ramBank    uint8  // 0x4000-0x5FFF - 4-bit RAM bank number (3-bit on rumble carts)
+
+	hasRumble bool
+	rumbling  bool
+	onRumble  func(on bool)
+}
+
+func NewMBC5(gp *gamepak.GamePak) *MBC5 {
+	m := &MBC5{
+		rom:     gp,
+		ram:     newRAM(gp),
+		romBank: 1,
+	}
+
+	switch gp.CartridgeType() {
+	case gamepak.MBC5_RUMBLE, gamepak.MBC5_RUMBLE_RAM, gamepak.MBC5_RUMBLE_RAM_BATTERY:
+		m.hasRumble = true
+	}
+
+	return m
+}
+
+// OnRumble registers a function that is called whenever the rumble motor turns on or off.
+func (m *MBC5) OnRumble(f func(on bool)) {
+	m.onRumble = f
+}
+
+func (m *MBC5) ReadROM(addr uint16) uint8 {
+	return m.rom.ReadOffset(m.ROMBank(addr)*romBankSize + uint(addr&0x3FFF))
+}
+
+// ROMBank returns the ROM bank mapped at addr.
+func (m *MBC5) ROMBank(addr uint16) uint {
+	if addr < 0x4000 {
+		return 0
+	}
+	return uint(m.romBank)
+}
+
+func (m *MBC5) WriteROM(addr uint16, data uint8) {
+	switch {
+	case addr < 0x2000:
+		m.ramEnabled = data == 0x0A
+	case addr < 0x3000:
+		m.romBank = m.romBank&0x100 | uint16(data)
+	case addr < 0x4000:
+		m.romBank = m.romBank&0xFF | uint16(data&0x01)<<8
+	case addr < 0x6000:
+		if m.hasRumble {
+			m.setRumble(data&(1<<3) != 0)
+			m.ramBank = data & 0x07
+		} else {
+			m.ramBank = data & 0x0F
+		}
+	}
+}
+
+func (m *MBC5) ReadRAM(addr uint16) uint8 {
+	if !m.ramEnabled || len(m.ram) == 0 {
+		return 0xFF
+	}
+	return m.ram[m.ramOffset(addr)]
+}
+
+func (m *MBC5) WriteRAM(addr uint16, data uint8) {
+	if !m.ramEnabled || len(m.ram) == 0 {
+		return
+	}
+	m.ram[m.ramOffset(addr)] = data
+}
+
+func (m *MBC5) ramOffset(addr uint16) int {
+	return (int(m.ramBank)*ramBankSize + int(addr)) % len(m.ram)
+}
+
+func (m *MBC5) setRumble(on bool) {
+	if on == m.rumbling {
+		return
+	}
+	m.rumbling = on
+	if m.onRumble != nil {
+		m.onRumble(on)
+	}
+}
+
+func (m *MBC5) SaveData() []byte {
+	return copyRAM(m.ram)
+}
+
+func (m *MBC5) LoadSaveData(data []byte) {
+	copy(m.ram, data)
+}
@@ -0,0 +1,78 @@
+package mbc
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+func TestMBC5_ROMBanking(t *testing.T) {
+	m := NewMBC5(gamepak.NewGamePak(newTestROM(gamepak.MBC5, 512, 0)))
+
+	tests := []struct {
+		name string
+		low  uint8
+		high uint8
+		want uint16
+	}{
+		{"Bank 0 is allowed", 0x00, 0x00, 0x000},
+		{"Bank 0xFF", 0xFF, 0x00, 0x0FF},
+		{"Ninth bit", 0x00, 0x01, 0x100},
+		{"Highest bank", 0xFF, 0x01, 0x1FF},
+		{"Only bit 0 of the high register is used", 0x02, 0xFE, 0x002},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m.WriteROM(0x2000, tc.low)
+			m.WriteROM(0x3000, tc.high)
+
+			got := uint16(m.ReadROM(0x4000)) | uint16(m.ReadROM(0x4001))<<8
+			if got != tc.want {
+				t.Errorf("got bank 0x%03X, want 0x%03X", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMBC5_RAMBanking(t *testing.T) {
+	m := NewMBC5(gamepak.NewGamePak(newTestROM(gamepak.MBC5_RAM, 8, 4))) // 128 KiB RAM
+	m.WriteROM(0x0000, 0x0A)
+
+	for bank := uint8(0); bank < 16; bank++ {
+		m.WriteROM(0x4000, bank)
+		m.WriteRAM(0x0100, bank)
+	}
+	for bank := uint8(0); bank < 16; bank++ {
+		m.WriteROM(0x4000, bank)
+		if got := m.ReadRAM(0x0100); got != bank {
+			t.Errorf("bank %d: got 0x%02X", bank, got)
+		}
+	}
+}
+
+func TestMBC5_Rumble(t *testing.T) {
+	m := NewMBC5(gamepak.NewGamePak(newTestROM(gamepak.MBC5_RUMBLE_RAM, 8, 3)))
+	m.WriteROM(0x0000, 0x0A)
+
+	var events []bool
+	m.OnRumble(func(on bool) {
+		events = append(events, on)
+	})
+
+	m.WriteROM(0x4000, 0x01)
+	m.WriteRAM(0x0000, 0xAB)
+
+	// Bit 3 turns the motor on without changing the RAM bank
+	m.WriteROM(0x4000, 0x09)
+	if got := m.ReadRAM(0x0000); got != 0xAB {
+		t.Errorf("RAM bank changed by rumble bit: got 0x%02X, want 0xAB", got)
+	}
+	m.WriteROM(0x4000, 0x09)
+	m.WriteROM(0x4000, 0x01)
+
+	want := []bool{true, false}
+	if len(events) != len(want) || events[0] != want[0] || events[1] != want[1] {
+		t.Errorf("got rumble events %v, want %v", events, want)
+	}
+}
@@ -0,0 +1,128 @@
+package mbc
+
+import "github.com/colecrouter/gameboy-go/private/reader/gamepak"
+
+const (
+	accelerometerCenter = 0x81D0 // Reading when flat
+	accelerometerPerG   = 0x70   // Change per g of acceleration
+)
+
+// MBC7 has up to 2 MiB of ROM, a two-axis accelerometer and a 93LC56 EEPROM instead of RAM.
+// https://gbdev.io/pandocs/MBC7.html
+type MBC7 struct {
+	rom    *gamepak.GamePak
+	eeprom *eeprom
+	accel  *Accelerometer
+
+	ramEnabled1 bool  // 0x0000-0x1FFF
+	romBank     uint8 // 0x2000-0x3FFF - 7-bit ROM bank number
+	ramEnabled2 bool  // 0x4000-0x5FFF
+
+	latchReady bool // Set by erasing the latch, cleared by latching
+	x, y       uint16
+}
+
+func NewMBC7(gp *gamepak.GamePak) *MBC7 {
+	return &MBC7{
+		rom:     gp,
+		eeprom:  newEEPROM(),
+		romBank: 1,
+		x:       0x8000,
+		y:       0x8000,
+	}
+}
+
+// ConnectAccelerometer sets where tilt readings come from.
+func (m *MBC7) ConnectAccelerometer(a *Accelerometer) {
+	m.accel = a
+}
+
+func (m *MBC7) ReadROM(addr uint16) uint8 {
+	return m.rom.ReadOffset(m.ROMBank(addr)*romBankSize + uint(addr&0x3FFF))
+}
+
+// ROMBank returns the ROM bank mapped at addr.
+func (m *MBC7) ROMBank(addr uint16) uint {
+	if addr < 0x4000 {
+		return 0
+	}
+	return uint(m.romBank)
+}
+
+func (m *MBC7) WriteROM(addr uint16, data uint8) {
+	switch {
+	case addr < 0x2000:
+		m.ramEnabled1 = data == 0x0A
+	case addr < 0x4000:
+		m.romBank = data & 0x7F
+	case addr < 0x6000:
+		m.ramEnabled2 = data == 0x40
+	}
+}
+
+// ReadRAM reads the registers at 0xA000-0xAFFF. Bits 4-7 of the address select the register.
+func (m *MBC7) ReadRAM(addr uint16) uint8 {
+	if !m.enabled(addr) {
+		return 0xFF
+	}
+
+	switch addr >> 4 & 0x0F {
+	case 0x2:
+		return uint8(m.x)
+	case 0x3:
+		return uint8(m.x >> 8)
+	case 0x4:
+		return uint8(m.y)
+	case 0x5:
+		return uint8(m.y >> 8)
+	case 0x6:
+		return 0x00
+	case 0x8:
+		return m.eeprom.read()
+	default:
+		return 0xFF
+	}
+}
+
+func (m *MBC7) WriteRAM(addr uint16, data uint8) {
+	if !m.enabled(addr) {
+		return
+	}
+
+	switch addr >> 4 & 0x0F {
+	case 0x0:
+		if data == 0x55 {
+			m.latchReady = true
+			m.x, m.y = 0x8000, 0x8000
+		}
+	case 0x1:
+		if data == 0xAA && m.latchReady {
+			m.latch()
+			m.latchReady = false
+		}
+	case 0x8:
+		m.eeprom.write(data)
+	}
+}
+
+func (m *MBC7) enabled(addr uint16) bool {
+	return m.ramEnabled1 && m.ramEnabled2 && addr < 0x1000
+}
+
+// latch samples the accelerometer into the X and Y registers.
+func (m *MBC7) latch() {
+	var x, y float64
+	if m.accel != nil {
+		x, y = m.accel.Tilt()
+	}
+	m.x = uint16(accelerometerCenter + int(x*accelerometerPerG))
+	m.y = uint16(accelerometerCenter + int(y*accelerometerPerG))
+}
+
+func (m *MBC7) SaveData() []byte {
+	return m.eeprom.bytes()
+}
+
+func (m *MBC7) LoadSaveData(data []byte) {
+	m.eeprom.load(data)
+}
@@ -0,0 +1,107 @@
+package mbc
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+func newTestMBC7() *MBC7 {
+	m := NewMBC7(gamepak.NewGamePak(newTestROM(gamepak.MBC7_SENSOR_RUMBLE_RAM_BATTERY, 64, 0)))
+	m.WriteROM(0x0000, 0x0A)
+	m.WriteROM(0x4000, 0x40)
+	return m
+}
+
+// eepromSend clocks bits into the EEPROM, most significant first, and returns what DO read after each bit.
+func eepromSend(m *MBC7, val uint32, bits int) uint32 {
+	var out uint32
+	for i := bits - 1; i >= 0; i-- {
+		di := uint8(val>>i&1) << 1
+		m.WriteRAM(0x0080, 0x80|di)
+		m.WriteRAM(0x0080, 0xC0|di)
+		out = out<<1 | uint32(m.ReadRAM(0x0080)&0x01)
+	}
+	return out
+}
+
+// sendEEPROMCommand sends a start bit, opcode and address, leaving CS high.
+func sendEEPROMCommand(m *MBC7, op, addr uint8) {
+	m.WriteRAM(0x0080, 0x00)
+	m.WriteRAM(0x0080, 0x80)
+	eepromSend(m, 1<<10|uint32(op)<<8|uint32(addr), 11)
+}
+
+func TestMBC7_Accelerometer(t *testing.T) {
+	m := newTestMBC7()
+	accel := &Accelerometer{}
+	m.ConnectAccelerometer(accel)
+	accel.SetTilt(1, -0.5)
+
+	// Latching without erasing first does nothing
+	m.WriteRAM(0x0010, 0xAA)
+	if got := m.ReadRAM(0x0030); got != 0x80 {
+		t.Errorf("X high before erase: got 0x%02X, want 0x80", got)
+	}
+
+	m.WriteRAM(0x0000, 0x55)
+	m.WriteRAM(0x0010, 0xAA)
+
+	x := uint16(m.ReadRAM(0x0030))<<8 | uint16(m.ReadRAM(0x0020))
+	y := uint16(m.ReadRAM(0x0050))<<8 | uint16(m.ReadRAM(0x0040))
+	if x != 0x81D0+0x70 || y != 0x81D0-0x38 {
+		t.Errorf("got X=0x%04X Y=0x%04X, want X=0x%04X Y=0x%04X", x, y, 0x81D0+0x70, 0x81D0-0x38)
+	}
+
+	// Latched values hold until the next latch
+	accel.SetTilt(0, 0)
+	if got := m.ReadRAM(0x0020); got != uint8(x) {
+		t.Errorf("X low changed without latch: got 0x%02X", got)
+	}
+}
+
+func TestMBC7_Disabled(t *testing.T) {
+	m := NewMBC7(gamepak.NewGamePak(newTestROM(gamepak.MBC7_SENSOR_RUMBLE_RAM_BATTERY, 64, 0)))
+	m.WriteROM(0x0000, 0x0A)
+	if got := m.ReadRAM(0x0060); got != 0xFF {
+		t.Errorf("half enabled: got 0x%02X, want 0xFF", got)
+	}
+}
+
+func TestMBC7_EEPROM(t *testing.T) {
+	m := newTestMBC7()
+
+	// Writes are ignored until enabled
+	sendEEPROMCommand(m, eepromOpWrite, 0x05)
+	eepromSend(m, 0x1234, 16)
+	sendEEPROMCommand(m, eepromOpRead, 0x05)
+	if got := eepromSend(m, 0, 16); got != 0xFFFF {
+		t.Errorf("write while disabled: got 0x%04X, want 0xFFFF", got)
+	}
+
+	sendEEPROMCommand(m, eepromOpExtended, 0xC0) // EWEN
+	sendEEPROMCommand(m, eepromOpWrite, 0x05)
+	eepromSend(m, 0x1234, 16)
+	if got := m.ReadRAM(0x0080) & 0x01; got != 1 {
+		t.Errorf("not ready after write")
+	}
+	sendEEPROMCommand(m, eepromOpWrite, 0x06)
+	eepromSend(m, 0xBEEF, 16)
+
+	// Sequential read across both words
+	sendEEPROMCommand(m, eepromOpRead, 0x05)
+	if got := eepromSend(m, 0, 32); got != 0x1234BEEF {
+		t.Errorf("read: got 0x%08X, want 0x1234BEEF", got)
+	}
+
+	sendEEPROMCommand(m, eepromOpErase, 0x05)
+	sendEEPROMCommand(m, eepromOpRead, 0x05)
+	if got := eepromSend(m, 0, 16); got != 0xFFFF {
+		t.Errorf("erase: got 0x%04X, want 0xFFFF", got)
+	}
+
+	data := m.SaveData()
+	if len(data) != 256 || data[12] != 0xEF || data[13] != 0xBE {
+		t.Errorf("save data: got %d bytes, word 6 = 0x%02X%02X", len(data), data[13], data[12])
+	}
+}
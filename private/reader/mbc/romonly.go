@@ -0,0 +1,48 @@
+package mbc
+
+import "github.com/colecrouter/gameboy-go/private/reader/gamepak"
+
+// ROMOnly is a cartridge without a mapper, optionally with up to 8 KiB of RAM.
+type ROMOnly struct {
+	rom *gamepak.GamePak
+	ram []byte
+}
+
+func NewROMOnly(gp *gamepak.GamePak) *ROMOnly {
+	return &ROMOnly{rom: gp, ram: newRAM(gp)}
+}
+
+func (r *ROMOnly) ReadROM(addr uint16) uint8 {
+	return r.rom.ReadOffset(uint(addr))
+}
+
+// ROMBank returns the ROM bank at addr. Without a mapper, 0x4000-0x7FFF is always bank 1.
+func (r *ROMOnly) ROMBank(addr uint16) uint {
+	return uint(addr / romBankSize)
+}
+
+func (r *ROMOnly) WriteROM(addr uint16, data uint8) {
+	// No registers to write to
+}
+
+func (r *ROMOnly) ReadRAM(addr uint16) uint8 {
+	if len(r.ram) == 0 {
+		return 0xFF
+	}
+	return r.ram[int(addr)%len(r.ram)]
+}
+
+func (r *ROMOnly) WriteRAM(addr uint16, data uint8) {
+	if len(r.ram) == 0 {
+		return
+	}
+	r.ram[int(addr)%len(r.ram)] = data
+}
+
+func (r *ROMOnly) SaveData() []byte {
+	return copyRAM(r.ram)
+}
+
+func (r *ROMOnly) LoadSaveData(data []byte) {
+	copy(r.ram, data)
+}
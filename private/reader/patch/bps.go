@@ -0,0 +1,106 @@
+package patch
+
+const (
+	bpsSourceRead = iota
+	bpsTargetRead
+	bpsSourceCopy
+	bpsTargetCopy
+)
+
+// ApplyBPS applies a BPS patch, validating the source, target and patch CRC32s.
+func ApplyBPS(rom, patch []byte) ([]byte, error) {
+	if len(patch) < len(BPS_MAGIC)+12 {
+		return nil, ErrCorrupt
+	}
+	targetCRC, err := checkFooter(rom, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{data: patch, pos: len(BPS_MAGIC), end: len(patch) - 12}
+	sourceSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	targetSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	metadataSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if sourceSize != uint64(len(rom)) || targetSize > MAX_TARGET_SIZE || metadataSize > uint64(r.end-r.pos) {
+		return nil, ErrCorrupt
+	}
+	r.pos += int(metadataSize)
+
+	target := make([]byte, targetSize)
+	var out, sourceRel, targetRel int
+
+	for r.pos < r.end {
+		data, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		length := int(data>>2) + 1
+		// Lengths and offsets are checked against what's left, since adding them first could overflow
+		if length > len(target)-out {
+			return nil, ErrCorrupt
+		}
+
+		switch data & 3 {
+		case bpsSourceRead:
+			if length > len(rom)-out {
+				return nil, ErrCorrupt
+			}
+			copy(target[out:], rom[out:out+length])
+		case bpsTargetRead:
+			if length > r.end-r.pos {
+				return nil, ErrCorrupt
+			}
+			copy(target[out:], patch[r.pos:r.pos+length])
+			r.pos += length
+		case bpsSourceCopy:
+			if sourceRel, err = r.relative(sourceRel); err != nil {
+				return nil, err
+			}
+			if sourceRel < 0 || sourceRel > len(rom) || length > len(rom)-sourceRel {
+				return nil, ErrCorrupt
+			}
+			copy(target[out:], rom[sourceRel:sourceRel+length])
+			sourceRel += length
+		case bpsTargetCopy:
+			if targetRel, err = r.relative(targetRel); err != nil {
+				return nil, err
+			}
+			if targetRel < 0 || targetRel >= out {
+				return nil, ErrCorrupt
+			}
+			// Byte by byte, since the copy may overlap the bytes it's writing
+			for i := range length {
+				target[out+i] = target[targetRel+i]
+			}
+			targetRel += length
+		}
+		out += length
+	}
+
+	if err := checkTarget(target, targetCRC); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// relative reads a signed offset and applies it to base.
+func (r *reader) relative(base int) (int, error) {
+	data, err := r.varint()
+	if err != nil {
+		return 0, err
+	}
+	delta := int(data >> 1)
+	if data&1 != 0 {
+		delta = -delta
+	}
+	return base + delta, nil
+}
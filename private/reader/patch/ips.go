@@ -0,0 +1,66 @@
+package patch
+
+// IPS records are terminated by this marker, which is also why offset 0x454F46 can't be patched.
+var ipsEOF = []byte("EOF")
+
+// ApplyIPS applies an IPS patch. IPS carries no checksums, so only the structure is validated.
+func ApplyIPS(rom, patch []byte) ([]byte, error) {
+	out := append([]byte(nil), rom...)
+	pos := len(IPS_MAGIC)
+
+	for {
+		if pos+3 > len(patch) {
+			return nil, ErrCorrupt
+		}
+		if string(patch[pos:pos+3]) == string(ipsEOF) {
+			pos += 3
+			break
+		}
+		if pos+5 > len(patch) {
+			return nil, ErrCorrupt
+		}
+		offset := int(patch[pos])<<16 | int(patch[pos+1])<<8 | int(patch[pos+2])
+		size := int(patch[pos+3])<<8 | int(patch[pos+4])
+		pos += 5
+
+		if size == 0 {
+			// Run-length encoded record
+			if pos+3 > len(patch) {
+				return nil, ErrCorrupt
+			}
+			size = int(patch[pos])<<8 | int(patch[pos+1])
+			value := patch[pos+2]
+			pos += 3
+
+			out = grow(out, offset+size)
+			for i := range size {
+				out[offset+i] = value
+			}
+			continue
+		}
+
+		if pos+size > len(patch) {
+			return nil, ErrCorrupt
+		}
+		out = grow(out, offset+size)
+		copy(out[offset:], patch[pos:pos+size])
+		pos += size
+	}
+
+	// Some patchers append a 24-bit size to truncate the ROM to
+	if pos+3 <= len(patch) {
+		size := int(patch[pos])<<16 | int(patch[pos+1])<<8 | int(patch[pos+2])
+		if size < len(out) {
+			out = out[:size]
+		}
+	}
+
+	return out, nil
+}
+
+func grow(b []byte, size int) []byte {
+	if size <= len(b) {
+		return b
+	}
+	return append(b, make([]byte, size-len(b))...)
+}
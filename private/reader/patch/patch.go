@@ -0,0 +1,147 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	IPS_MAGIC = []byte("PATCH")
+	BPS_MAGIC = []byte("BPS1")
+	UPS_MAGIC = []byte("UPS1")
+
+	// Extensions searched for beside a ROM, in the order the patches are applied.
+	PATCH_EXTENSIONS = []string{".ips", ".ups", ".bps"}
+
+	// MAX_TARGET_SIZE is the largest patched ROM accepted, well beyond any real cartridge.
+	MAX_TARGET_SIZE uint64 = 8 << 20
+
+	ErrUnknownFormat = errors.New("unknown patch format")
+	ErrCorrupt       = errors.New("patch is truncated or corrupt")
+)
+
+// ChecksumError is returned when a BPS or UPS checksum doesn't match.
+type ChecksumError struct {
+	Which string // "source", "target" or "patch"
+	Want  uint32
+	Got   uint32
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: want %08X, got %08X", e.Which, e.Want, e.Got)
+}
+
+// Apply patches rom with an IPS, BPS or UPS patch, picked by its magic bytes.
+// The input ROM is never modified.
+func Apply(rom, patch []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(patch, IPS_MAGIC):
+		return ApplyIPS(rom, patch)
+	case bytes.HasPrefix(patch, BPS_MAGIC):
+		return ApplyBPS(rom, patch)
+	case bytes.HasPrefix(patch, UPS_MAGIC):
+		return ApplyUPS(rom, patch)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ApplyFiles applies each patch file to rom in order, so later patches see the output of earlier ones.
+func ApplyFiles(rom []byte, paths []string) ([]byte, error) {
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		rom, err = Apply(rom, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	return rom, nil
+}
+
+// Find returns the patches named after the ROM at romPath, e.g. game.ips beside game.gb.
+func Find(romPath string) []string {
+	base := strings.TrimSuffix(romPath, filepath.Ext(romPath))
+
+	var found []string
+	for _, ext := range PATCH_EXTENSIONS {
+		p := base + ext
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// checkFooter validates the source and patch CRC32s shared by the BPS and UPS footers,
+// and returns the expected target CRC32.
+func checkFooter(source, patch []byte) (uint32, error) {
+	footer := patch[len(patch)-12:]
+	sourceCRC := le32(footer[0:])
+	targetCRC := le32(footer[4:])
+	patchCRC := le32(footer[8:])
+
+	if got := crc32.ChecksumIEEE(patch[:len(patch)-4]); got != patchCRC {
+		return 0, &ChecksumError{Which: "patch", Want: patchCRC, Got: got}
+	}
+	if got := crc32.ChecksumIEEE(source); got != sourceCRC {
+		return 0, &ChecksumError{Which: "source", Want: sourceCRC, Got: got}
+	}
+	return targetCRC, nil
+}
+
+func checkTarget(target []byte, want uint32) error {
+	if got := crc32.ChecksumIEEE(target); got != want {
+		return &ChecksumError{Which: "target", Want: want, Got: got}
+	}
+	return nil
+}
+
+func le32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+// reader walks the body of a BPS or UPS patch.
+type reader struct {
+	data []byte
+	pos  int
+	end  int
+}
+
+func (r *reader) byte() (uint8, error) {
+	if r.pos >= r.end {
+		return 0, ErrCorrupt
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+// varint decodes the number encoding used by BPS and UPS, where each continuation also adds an offset
+// so that every value has exactly one encoding.
+func (r *reader) varint() (uint64, error) {
+	var value uint64
+	shift := uint64(1)
+	for {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		value += uint64(b&0x7F) * shift
+		if b&0x80 != 0 {
+			return value, nil
+		}
+		shift <<= 7
+		value += shift
+		if shift > 1<<56 {
+			return 0, ErrCorrupt
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func encodeVarint(v uint64) []byte {
+	var out []byte
+	for {
+		x := uint8(v & 0x7F)
+		v >>= 7
+		if v == 0 {
+			return append(out, x|0x80)
+		}
+		out = append(out, x)
+		v--
+	}
+}
+
+func appendLE32(b []byte, v uint32) []byte {
+	return append(b, uint8(v), uint8(v>>8), uint8(v>>16), uint8(v>>24))
+}
+
+// finish appends the BPS/UPS footer to a patch body.
+func finish(body, source, target []byte) []byte {
+	body = appendLE32(body, crc32.ChecksumIEEE(source))
+	body = appendLE32(body, crc32.ChecksumIEEE(target))
+	return appendLE32(body, crc32.ChecksumIEEE(body))
+}
+
+func TestVarint(t *testing.T) {
+	for _, v := range []uint64{0, 1, 0x7F, 0x80, 0x3FFF, 0x4000, 0x123456} {
+		r := &reader{data: encodeVarint(v)}
+		r.end = len(r.data)
+		got, err := r.varint()
+		if err != nil || got != v {
+			t.Errorf("varint(%X) = %X, %v", v, got, err)
+		}
+	}
+}
+
+func TestIPS(t *testing.T) {
+	rom := []byte{0, 1, 2, 3, 4, 5, 6, 7}
+
+	patch := append([]byte{}, IPS_MAGIC...)
+	patch = append(patch, 0x00, 0x00, 0x01, 0x00, 0x02, 0xAA, 0xBB)       // Write 2 bytes at 1
+	patch = append(patch, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x04, 0xCC) // Fill 4 bytes at 6
+	patch = append(patch, ipsEOF...)
+
+	got, err := ApplyIPS(rom, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0, 0xAA, 0xBB, 3, 4, 5, 0xCC, 0xCC, 0xCC, 0xCC}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got % X, want % X", got, want)
+	}
+	if rom[1] != 1 {
+		t.Error("input ROM was modified")
+	}
+
+	t.Run("Truncate", func(t *testing.T) {
+		got, err := ApplyIPS(rom, append(append(append([]byte{}, IPS_MAGIC...), ipsEOF...), 0x00, 0x00, 0x04))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, rom[:4]) {
+			t.Errorf("got % X, want % X", got, rom[:4])
+		}
+	})
+
+	t.Run("Truncated patch", func(t *testing.T) {
+		if _, err := ApplyIPS(rom, patch[:len(patch)-4]); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("got %v, want ErrCorrupt", err)
+		}
+	})
+}
+
+func TestBPS(t *testing.T) {
+	source := []byte("ABCDEFGH")
+	target := []byte("ABxyEFCDCDCDCD")
+
+	body := append([]byte{}, BPS_MAGIC...)
+	body = append(body, encodeVarint(uint64(len(source)))...)
+	body = append(body, encodeVarint(uint64(len(target)))...)
+	body = append(body, encodeVarint(3)...)
+	body = append(body, "m=1"...)
+	body = append(body, encodeVarint((2-1)<<2|bpsSourceRead)...) // AB
+	body = append(body, encodeVarint((2-1)<<2|bpsTargetRead)...) // xy
+	body = append(body, "xy"...)
+	body = append(body, encodeVarint((2-1)<<2|bpsSourceRead)...) // EF
+	body = append(body, encodeVarint((2-1)<<2|bpsSourceCopy)...) // CD
+	body = append(body, encodeVarint(2<<1)...)                   // source +2
+	body = append(body, encodeVarint((6-1)<<2|bpsTargetCopy)...) // CDCDCD, overlapping
+	body = append(body, encodeVarint(6<<1)...)                   // target +6
+	patch := finish(body, source, target)
+
+	got, err := ApplyBPS(source, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, target) {
+		t.Errorf("got %q, want %q", got, target)
+	}
+
+	t.Run("Wrong source", func(t *testing.T) {
+		_, err := ApplyBPS([]byte("ABCDEFGX"), patch)
+		var ce *ChecksumError
+		if !errors.As(err, &ce) || ce.Which != "source" {
+			t.Errorf("got %v, want source checksum error", err)
+		}
+	})
+
+	t.Run("Corrupt patch", func(t *testing.T) {
+		bad := append([]byte{}, patch...)
+		bad[len(BPS_MAGIC)+5] ^= 0xFF
+		_, err := ApplyBPS(source, bad)
+		var ce *ChecksumError
+		if !errors.As(err, &ce) || ce.Which != "patch" {
+			t.Errorf("got %v, want patch checksum error", err)
+		}
+	})
+}
+
+func TestUPS(t *testing.T) {
+	source := []byte{1, 2, 3, 4, 5, 6}
+	target := []byte{1, 9, 3, 4, 5, 6, 0, 7}
+
+	body := append([]byte{}, UPS_MAGIC...)
+	body = append(body, encodeVarint(uint64(len(source)))...)
+	body = append(body, encodeVarint(uint64(len(target)))...)
+	body = append(body, encodeVarint(1)...)
+	body = append(body, 2^9, 0)
+	body = append(body, encodeVarint(4)...)
+	body = append(body, 7, 0)
+
+	got, err := ApplyUPS(source, finish(body, source, target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, target) {
+		t.Errorf("got % X, want % X", got, target)
+	}
+
+	t.Run("Wrong target", func(t *testing.T) {
+		_, err := ApplyUPS(source, finish(body, source, []byte{1}))
+		var ce *ChecksumError
+		if !errors.As(err, &ce) || ce.Which != "target" {
+			t.Errorf("got %v, want target checksum error", err)
+		}
+	})
+}
+
+func TestApplyFiles(t *testing.T) {
+	dir := t.TempDir()
+	romPath := filepath.Join(dir, "game.gb")
+	rom := []byte{0, 0, 0, 0}
+
+	// game.ips sets byte 0, game.ups is built against the IPS output and sets byte 1
+	ips := append(append([]byte{}, IPS_MAGIC...), 0, 0, 0, 0, 1, 0x11)
+	ips = append(ips, ipsEOF...)
+	afterIPS := []byte{0x11, 0, 0, 0}
+	final := []byte{0x11, 0x22, 0, 0}
+	ups := append([]byte{}, UPS_MAGIC...)
+	ups = append(ups, encodeVarint(4)...)
+	ups = append(ups, encodeVarint(4)...)
+	ups = append(ups, encodeVarint(1)...)
+	ups = append(ups, 0x22, 0)
+	ups = finish(ups, afterIPS, final)
+
+	os.WriteFile(filepath.Join(dir, "game.ips"), ips, 0o644)
+	os.WriteFile(filepath.Join(dir, "game.ups"), ups, 0o644)
+	os.WriteFile(filepath.Join(dir, "other.bps"), nil, 0o644)
+
+	found := Find(romPath)
+	if len(found) != 2 {
+		t.Fatalf("found %v, want game.ips and game.ups", found)
+	}
+
+	got, err := ApplyFiles(rom, found)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, final) {
+		t.Errorf("got % X, want % X", got, final)
+	}
+
+	// The other order fails, because the UPS source checksum no longer matches
+	if _, err := ApplyFiles(rom, []string{found[1], found[0]}); err == nil {
+		t.Error("expected an error applying patches out of order")
+	}
+}
+
+func TestApplyUnknown(t *testing.T) {
+	if _, err := Apply(nil, []byte("nope")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("got %v, want ErrUnknownFormat", err)
+	}
+}
+
+func TestOversizedTarget(t *testing.T) {
+	source := []byte("ABCD")
+	for _, magic := range [][]byte{BPS_MAGIC, UPS_MAGIC} {
+		body := append([]byte{}, magic...)
+		body = append(body, encodeVarint(uint64(len(source)))...)
+		body = append(body, encodeVarint(MAX_TARGET_SIZE+1)...)
+		if bytes.Equal(magic, BPS_MAGIC) {
+			body = append(body, encodeVarint(0)...) // metadata size
+		}
+		patch := finish(body, source, source)
+
+		if _, err := Apply(source, patch); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: got %v, want ErrCorrupt", magic, err)
+		}
+	}
+}
+
+func TestMaliciousPatch(t *testing.T) {
+	source := []byte("ABCD")
+	header := func(magic []byte) []byte {
+		body := append([]byte{}, magic...)
+		body = append(body, encodeVarint(uint64(len(source)))...)
+		body = append(body, encodeVarint(uint64(len(source)))...)
+		if bytes.Equal(magic, BPS_MAGIC) {
+			body = append(body, encodeVarint(0)...) // metadata size
+		}
+		return body
+	}
+
+	// Skips large enough to overflow the output position
+	ups := header(UPS_MAGIC)
+	for range 3 {
+		ups = append(ups, encodeVarint(1<<62)...)
+		ups = append(ups, 0x01, 0x00)
+	}
+
+	// A huge length, and a copy from a huge source offset
+	bpsLength := append(header(BPS_MAGIC), encodeVarint((1<<60)<<2|bpsTargetRead)...)
+	bpsOffset := append(header(BPS_MAGIC), encodeVarint((2-1)<<2|bpsSourceCopy)...)
+	bpsOffset = append(bpsOffset, encodeVarint(1<<62)...)
+
+	for name, body := range map[string][]byte{"UPS skip": ups, "BPS length": bpsLength, "BPS offset": bpsOffset} {
+		if _, err := Apply(source, finish(body, source, source)); !errors.Is(err, ErrCorrupt) {
+			t.Errorf("%s: got %v, want ErrCorrupt", name, err)
+		}
+	}
+}
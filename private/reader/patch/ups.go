@@ -0,0 +1,63 @@
+package patch
+
+// ApplyUPS applies a UPS patch, validating the source, target and patch CRC32s.
+func ApplyUPS(rom, patch []byte) ([]byte, error) {
+	if len(patch) < len(UPS_MAGIC)+12 {
+		return nil, ErrCorrupt
+	}
+	targetCRC, err := checkFooter(rom, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{data: patch, pos: len(UPS_MAGIC), end: len(patch) - 12}
+	sourceSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	targetSize, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if sourceSize != uint64(len(rom)) || targetSize > MAX_TARGET_SIZE {
+		return nil, ErrCorrupt
+	}
+
+	target := make([]byte, targetSize)
+	copy(target, rom)
+
+	// Hunks can run past a target smaller than the source, up to the source's size
+	limit := max(len(target), len(rom))
+	out := 0
+	for r.pos < r.end {
+		skip, err := r.varint()
+		if err != nil {
+			return nil, err
+		}
+		if out > limit || skip > uint64(limit-out) {
+			return nil, ErrCorrupt
+		}
+		out += int(skip)
+
+		// XOR bytes until a zero terminator, which also consumes one output byte
+		for {
+			b, err := r.byte()
+			if err != nil {
+				return nil, err
+			}
+			if b == 0 {
+				break
+			}
+			if out < len(target) {
+				target[out] ^= b
+			}
+			out++
+		}
+		out++
+	}
+
+	if err := checkTarget(target, targetCRC); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
@@ -1,27 +1,129 @@
 package reader
 
 import (
+	"sync/atomic"
+
+	"github.com/colecrouter/gameboy-go/private/memory"
 	bootroms "github.com/colecrouter/gameboy-go/private/memory/roms"
 	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+	"github.com/colecrouter/gameboy-go/private/reader/mbc"
 )
 
+// CartridgeReader is the cartridge slot. Peripherals connected to it stay connected across cartridge swaps,
+// and are handed to every cartridge inserted that can use them.
 type CartridgeReader struct {
 	disableBootRom *bool
-	cartridge      *gamepak.GamePak
+	bootROM        *bootroms.BootROM
+	slot           atomic.Pointer[slot]
+	onRumble       func(on bool)
+	infrared       mbc.InfraredPeer
+	camera         mbc.ImageSource
+	accelerometer  *mbc.Accelerometer
+	patcher        ROMPatcher
+}
+
+// slot is an inserted cartridge. It is swapped as a whole so the CPU never sees a half-inserted cartridge.
+type slot struct {
+	cartridge *gamepak.GamePak
+	mapper    mbc.MBC
+}
+
+// ROMPatcher can change the values read from cartridge ROM, e.g. to apply Game Genie codes.
+type ROMPatcher interface {
+	PatchROM(addr uint16, val uint8) uint8
 }
 
 func NewCartridgeReader(disableBootRom *bool) *CartridgeReader {
 	return &CartridgeReader{
 		disableBootRom: disableBootRom,
+		bootROM:        bootroms.DMG,
 	}
 }
 
+// SetBootROM sets the boot ROM mapped over the cartridge until 0xFF50 is written. Nil means there is none.
+func (cr *CartridgeReader) SetBootROM(b *bootroms.BootROM) {
+	cr.bootROM = b
+}
+
+// InsertCartridge inserts a cartridge, replacing any that is already inserted. It is safe to call while running.
 func (cr *CartridgeReader) InsertCartridge(game *gamepak.GamePak) {
-	cr.cartridge = game
+	mapper := mbc.New(game)
+
+	if r, ok := mapper.(mbc.Rumbler); ok {
+		r.OnRumble(cr.rumble)
+	}
+	if ir, ok := mapper.(mbc.Infrared); ok {
+		ir.ConnectInfrared(cr.infrared)
+	}
+	if cam, ok := mapper.(mbc.Camera); ok {
+		cam.ConnectCamera(cr.camera)
+	}
+	if tilt, ok := mapper.(mbc.TiltSensor); ok {
+		tilt.ConnectAccelerometer(cr.accelerometer)
+	}
+
+	if cr.slot.Swap(&slot{cartridge: game, mapper: mapper}) != nil {
+		cr.rumble(false)
+	}
+}
+
+// RemoveCartridge empties the cartridge slot. It is safe to call while running.
+func (cr *CartridgeReader) RemoveCartridge() {
+	if cr.slot.Swap(nil) != nil {
+		cr.rumble(false)
+	}
+}
+
+// ConnectAccelerometer sets where cartridges with a motion sensor read their tilt from.
+func (cr *CartridgeReader) ConnectAccelerometer(a *mbc.Accelerometer) {
+	cr.accelerometer = a
+	if tilt, ok := cr.Mapper().(mbc.TiltSensor); ok {
+		tilt.ConnectAccelerometer(a)
+	}
+}
+
+// ConnectCamera sets where the image sensor of camera cartridges gets its frames from.
+func (cr *CartridgeReader) ConnectCamera(src mbc.ImageSource) {
+	cr.camera = src
+	if cam, ok := cr.Mapper().(mbc.Camera); ok {
+		cam.ConnectCamera(src)
+	}
+}
+
+// ConnectInfrared connects the infrared port of cartridges that have one to a peer.
+func (cr *CartridgeReader) ConnectInfrared(p mbc.InfraredPeer) {
+	cr.infrared = p
+	if ir, ok := cr.Mapper().(mbc.Infrared); ok {
+		ir.ConnectInfrared(p)
+	}
 }
 
+// OnRumble registers a function that is called whenever the cartridge's rumble motor turns on or off.
+// It stays registered across cartridge swaps.
+func (cr *CartridgeReader) OnRumble(f func(on bool)) {
+	cr.onRumble = f
+}
+
+func (cr *CartridgeReader) rumble(on bool) {
+	if cr.onRumble != nil {
+		cr.onRumble(on)
+	}
+}
+
+// Cartridge returns the inserted cartridge, or nil if the slot is empty.
 func (cr *CartridgeReader) Cartridge() *gamepak.GamePak {
-	return cr.cartridge
+	if s := cr.slot.Load(); s != nil {
+		return s.cartridge
+	}
+	return nil
+}
+
+// Mapper returns the memory bank controller of the inserted cartridge, or nil if the slot is empty.
+func (cr *CartridgeReader) Mapper() mbc.MBC {
+	if s := cr.slot.Load(); s != nil {
+		return s.mapper
+	}
+	return nil
 }
 
 func (cr *CartridgeReader) Read(addr uint16) uint8 {
@@ -29,14 +131,55 @@ func (cr *CartridgeReader) Read(addr uint16) uint8 {
 		panic("Boot ROM disable flag not set")
 	}
 
-	if (!*cr.disableBootRom) && addr < 0x100 {
-		return bootroms.DMG_BOOT[addr]
+	if !*cr.disableBootRom && cr.bootROM != nil && cr.bootROM.Mapped(addr) {
+		return cr.bootROM.Data[addr]
+	}
+
+	// With no cartridge the boot ROM reads an 0xFF logo, fails its check and hangs, just like hardware
+	s := cr.slot.Load()
+	if s == nil {
+		return memory.OPEN_BUS
+	}
+
+	val := s.mapper.ReadROM(addr)
+	if cr.patcher != nil {
+		val = cr.patcher.PatchROM(addr, val)
 	}
-	return cr.cartridge.Read(addr)
+	return val
 }
 
+// SetROMPatcher sets a patcher that sees every ROM read. The boot ROM is never patched.
+func (cr *CartridgeReader) SetROMPatcher(p ROMPatcher) {
+	cr.patcher = p
+}
+
+// Write forwards writes to the mapper registers. The ROM itself is read-only.
 func (cr *CartridgeReader) Write(addr uint16, val uint8) {
-	// TODO implement cartridge write protection
-	// cr.cartridge.Write(addr, val)
-	// panic("Cartridge write protection not implemented")
+	if s := cr.slot.Load(); s != nil {
+		s.mapper.WriteROM(addr, val)
+	}
+}
+
+// ExternalRAM returns a device for the cartridge RAM window at 0xA000-0xBFFF.
+func (cr *CartridgeReader) ExternalRAM() *ExternalRAM {
+	return &ExternalRAM{reader: cr}
+}
+
+// ExternalRAM forwards accesses to 0xA000-0xBFFF to the inserted cartridge's mapper.
+type ExternalRAM struct {
+	reader *CartridgeReader
+}
+
+func (e *ExternalRAM) Read(addr uint16) uint8 {
+	s := e.reader.slot.Load()
+	if s == nil {
+		return memory.OPEN_BUS
+	}
+	return s.mapper.ReadRAM(addr)
+}
+
+func (e *ExternalRAM) Write(addr uint16, val uint8) {
+	if s := e.reader.slot.Load(); s != nil {
+		s.mapper.WriteRAM(addr, val)
+	}
 }
@@ -0,0 +1,105 @@
+package reader
+
+import (
+	"testing"
+
+	"github.com/colecrouter/gameboy-go/private/memory"
+	bootroms "github.com/colecrouter/gameboy-go/private/memory/roms"
+	"github.com/colecrouter/gameboy-go/private/reader/gamepak"
+)
+
+func TestCartridgeSlot(t *testing.T) {
+	bootDisabled := true
+	cr := NewCartridgeReader(&bootDisabled)
+	ram := cr.ExternalRAM()
+
+	t.Run("Empty", func(t *testing.T) {
+		for _, addr := range []uint16{0x0000, 0x0104, 0x4000, 0x7FFF} {
+			if got := cr.Read(addr); got != memory.OPEN_BUS {
+				t.Errorf("Read(%04X) = %02X, want %02X", addr, got, memory.OPEN_BUS)
+			}
+		}
+		if got := ram.Read(0x0000); got != memory.OPEN_BUS {
+			t.Errorf("RAM read = %02X, want %02X", got, memory.OPEN_BUS)
+		}
+		// Writes to an empty slot go nowhere
+		cr.Write(0x2000, 0x01)
+		ram.Write(0x0000, 0x01)
+	})
+
+	t.Run("Boot ROM without cartridge", func(t *testing.T) {
+		bootDisabled = false
+		defer func() { bootDisabled = true }()
+		if got := cr.Read(0x0000); got != bootroms.DMG_BOOT[0] {
+			t.Errorf("got %02X, want boot ROM byte %02X", got, bootroms.DMG_BOOT[0])
+		}
+		if got := cr.Read(0x0104); got != memory.OPEN_BUS {
+			t.Errorf("logo read = %02X, want %02X", got, memory.OPEN_BUS)
+		}
+	})
+
+	rom := make([]byte, 0x8000)
+	rom[0x0150] = 0x42
+	cr.InsertCartridge(gamepak.NewGamePak(rom))
+
+	t.Run("Inserted", func(t *testing.T) {
+		if cr.Cartridge() == nil || cr.Mapper() == nil {
+			t.Fatal("cartridge not inserted")
+		}
+		if got := cr.Read(0x0150); got != 0x42 {
+			t.Errorf("got %02X, want 42", got)
+		}
+	})
+
+	t.Run("Swapped", func(t *testing.T) {
+		other := make([]byte, 0x8000)
+		other[0x0150] = 0x24
+		cr.InsertCartridge(gamepak.NewGamePak(other))
+		if got := cr.Read(0x0150); got != 0x24 {
+			t.Errorf("got %02X, want 24", got)
+		}
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		cr.RemoveCartridge()
+		if cr.Cartridge() != nil || cr.Mapper() != nil {
+			t.Error("slot not empty")
+		}
+		if got := cr.Read(0x0150); got != memory.OPEN_BUS {
+			t.Errorf("got %02X, want %02X", got, memory.OPEN_BUS)
+		}
+	})
+}
+
+func TestUninitializedGamePak(t *testing.T) {
+	var gp gamepak.GamePak
+	if got := gp.Read(0x0100); got != memory.OPEN_BUS {
+		t.Errorf("Read = %02X, want %02X", got, memory.OPEN_BUS)
+	}
+	if got := gp.ReadOffset(0x0100); got != memory.OPEN_BUS {
+		t.Errorf("ReadOffset = %02X, want %02X", got, memory.OPEN_BUS)
+	}
+	gp.Write(0x0100, 0x00)
+}
+
+func TestShortROM(t *testing.T) {
+	bootDisabled := true
+	cr := NewCartridgeReader(&bootDisabled)
+
+	// Too short to have a header, which reads as zeros: a ROM-only cartridge
+	game := gamepak.NewGamePak([]byte{0x00: 0x3C, 0xFF: 0x7E})
+	cr.InsertCartridge(game)
+
+	if got := game.CartridgeType(); got != gamepak.ROM_ONLY {
+		t.Errorf("cartridge type = %v, want %v", got, gamepak.ROM_ONLY)
+	}
+	if got := game.CGBFlag(); got != gamepak.CGB_NONE {
+		t.Errorf("CGB flag = %v, want %v", got, gamepak.CGB_NONE)
+	}
+	if got := game.Title(); got != "" {
+		t.Errorf("title = %q, want none", got)
+	}
+	if got := cr.Read(0x00FF); got != 0x7E {
+		t.Errorf("Read(00FF) = %02X, want 7E", got)
+	}
+}
@@ -0,0 +1,137 @@
+package rom
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+var (
+	ZIP_MAGIC        = []byte{'P', 'K', 0x03, 0x04}
+	ZIP_EMPTY_MAGIC  = []byte{'P', 'K', 0x05, 0x06}
+	GZIP_MAGIC       = []byte{0x1F, 0x8B}
+	SEVEN_ZIP_MAGIC  = []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}
+	ROM_EXTENSIONS   = []string{".gb", ".gbc"}
+	ErrNoROM         = errors.New("archive contains no .gb or .gbc file")
+	ErrUnsupported7z = errors.New("7z archives are not supported")
+	ErrTooLarge      = errors.New("unpacked ROM is too large")
+)
+
+// MAX_ROM_SIZE is the most an archive may unpack to, well beyond any real cartridge.
+const MAX_ROM_SIZE = 8 << 20
+
+// MultipleROMsError is returned when an archive holds more than one ROM and it isn't clear which to load.
+type MultipleROMsError struct {
+	Names []string
+}
+
+func (e *MultipleROMsError) Error() string {
+	return fmt.Sprintf("archive contains multiple ROMs: %s", strings.Join(e.Names, ", "))
+}
+
+// Load reads a ROM from disk, unpacking it first if it is a zip or gzip archive.
+func Load(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// Decode returns the raw ROM image inside data, which may be a bare ROM, a zip archive or a gzip stream.
+// Archives are recognised by their magic bytes, not by file name.
+func Decode(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, ZIP_MAGIC), bytes.HasPrefix(data, ZIP_EMPTY_MAGIC):
+		return decodeZip(data)
+	case bytes.HasPrefix(data, GZIP_MAGIC):
+		return decodeGzip(data)
+	case bytes.HasPrefix(data, SEVEN_ZIP_MAGIC):
+		return nil, ErrUnsupported7z
+	default:
+		return data, nil
+	}
+}
+
+func decodeZip(data []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("zip: %w", err)
+	}
+
+	var candidates []*zip.File
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		if isROMName(f.Name) {
+			candidates = append(candidates, f)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, ErrNoROM
+	case 1:
+	default:
+		names := make([]string, len(candidates))
+		for i, f := range candidates {
+			names[i] = f.Name
+		}
+		return nil, &MultipleROMsError{Names: names}
+	}
+
+	rc, err := candidates[0].Open()
+	if err != nil {
+		return nil, fmt.Errorf("zip: %w", err)
+	}
+	defer rc.Close()
+
+	rom, err := readLimited(rc)
+	if err != nil {
+		return nil, fmt.Errorf("zip: %s: %w", candidates[0].Name, err)
+	}
+	return rom, nil
+}
+
+func decodeGzip(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	defer zr.Close()
+
+	rom, err := readLimited(zr)
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	return rom, nil
+}
+
+// readLimited reads an unpacked ROM, giving up past MAX_ROM_SIZE so a small archive can't expand to fill memory.
+func readLimited(r io.Reader) ([]byte, error) {
+	rom, err := io.ReadAll(io.LimitReader(r, MAX_ROM_SIZE+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(rom) > MAX_ROM_SIZE {
+		return nil, ErrTooLarge
+	}
+	return rom, nil
+}
+
+func isROMName(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, e := range ROM_EXTENSIONS {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package rom
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"testing"
+)
+
+func testROM() []byte {
+	rom := make([]byte, 0x8000)
+	for i := range rom {
+		rom[i] = uint8(i)
+	}
+	return rom
+}
+
+func makeZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	rom := testROM()
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(rom)
+	zw.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"Raw", rom},
+		{"Gzip", gz.Bytes()},
+		{"Zip", makeZip(t, map[string][]byte{"game.gb": rom, "readme.txt": []byte("hi")})},
+		{"Zip GBC upper case", makeZip(t, map[string][]byte{"dir/GAME.GBC": rom})},
+		{"Zip ignores macOS metadata", makeZip(t, map[string][]byte{"game.gb": rom, "__MACOSX/._game.gb": {0}})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, rom) {
+				t.Errorf("decoded %d bytes, want the %d byte ROM", len(got), len(rom))
+			}
+		})
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Run("No ROM", func(t *testing.T) {
+		_, err := Decode(makeZip(t, map[string][]byte{"readme.txt": nil}))
+		if !errors.Is(err, ErrNoROM) {
+			t.Errorf("got %v, want ErrNoROM", err)
+		}
+	})
+
+	t.Run("Multiple ROMs", func(t *testing.T) {
+		_, err := Decode(makeZip(t, map[string][]byte{"a.gb": {1}, "b.gbc": {2}}))
+		var multi *MultipleROMsError
+		if !errors.As(err, &multi) {
+			t.Fatalf("got %v, want MultipleROMsError", err)
+		}
+		if len(multi.Names) != 2 {
+			t.Errorf("got candidates %v, want 2", multi.Names)
+		}
+	})
+
+	t.Run("7z", func(t *testing.T) {
+		_, err := Decode(append(append([]byte{}, SEVEN_ZIP_MAGIC...), 0, 4))
+		if !errors.Is(err, ErrUnsupported7z) {
+			t.Errorf("got %v, want ErrUnsupported7z", err)
+		}
+	})
+
+	t.Run("Too large", func(t *testing.T) {
+		// Zeros compress well, so these archives are tiny
+		huge := make([]byte, MAX_ROM_SIZE+1)
+		var gz bytes.Buffer
+		zw := gzip.NewWriter(&gz)
+		zw.Write(huge)
+		zw.Close()
+
+		for name, data := range map[string][]byte{
+			"gzip": gz.Bytes(),
+			"zip":  makeZip(t, map[string][]byte{"game.gb": huge}),
+		} {
+			if _, err := Decode(data); !errors.Is(err, ErrTooLarge) {
+				t.Errorf("%s: got %v, want ErrTooLarge", name, err)
+			}
+		}
+	})
+
+	t.Run("Corrupt gzip", func(t *testing.T) {
+		if _, err := Decode([]byte{0x1F, 0x8B, 0}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package save
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage persists the battery-backed RAM of a single cartridge.
+// Load should return an error wrapping fs.ErrNotExist if nothing has been saved yet.
+type Storage interface {
+	Load() ([]byte, error)
+	Store(data []byte) error
+}
+
+// File keeps saves in a file on disk.
+type File struct {
+	Path string
+}
+
+// NewFile returns storage for the .sav file next to the given ROM.
+func NewFile(romPath string) *File {
+	return &File{Path: strings.TrimSuffix(romPath, filepath.Ext(romPath)) + ".sav"}
+}
+
+func (f *File) Load() ([]byte, error) {
+	return os.ReadFile(f.Path)
+}
+
+// Store writes the save to a temporary file and renames it over the old one,
+// so a crash mid-write never leaves a truncated save behind.
+func (f *File) Store(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.Path)
+}
@@ -0,0 +1,48 @@
+package save
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFile(t *testing.T) {
+	f := NewFile(filepath.Join("roms", "game.gbc"))
+	if want := filepath.Join("roms", "game.sav"); f.Path != want {
+		t.Errorf("got %q, want %q", f.Path, want)
+	}
+}
+
+func TestFile_StoreLoad(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFile(filepath.Join(dir, "game.gb"))
+
+	if _, err := f.Load(); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist before the first save, got %v", err)
+	}
+
+	for _, data := range [][]byte{{0x01, 0x02, 0x03}, {0x04}} {
+		if err := f.Store(data); err != nil {
+			t.Fatal(err)
+		}
+		got, err := f.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("got %v, want %v", got, data)
+		}
+	}
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the save file, found %d entries", len(entries))
+	}
+}
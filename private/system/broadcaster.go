@@ -2,6 +2,7 @@ package system
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
 // ClockType represents the type of clock cycle & edge.
@@ -19,6 +20,18 @@ type Broadcaster struct {
 	subs    [4][]chan struct{}
 	ackSubs [4][]chan struct{}
 	count   int64
+	double  atomic.Bool
+}
+
+// SetDoubleSpeed sets whether M-cycles take 2 T-cycles instead of 4, as in CGB double speed mode.
+// T-cycles keep their rate, so the PPU runs at the same speed either way.
+func (b *Broadcaster) SetDoubleSpeed(on bool) {
+	b.double.Store(on)
+}
+
+// DoubleSpeed reports whether M-cycles are running at double speed.
+func (b *Broadcaster) DoubleSpeed() bool {
+	return b.double.Load()
 }
 
 // Subscribe adds a new subscriber for the specified clock type.
@@ -60,7 +73,12 @@ func (b *Broadcaster) broadcastWithAck(c ClockType) {
 }
 
 func (b *Broadcaster) TClock() {
-	if b.count%4 == 0 {
+	period := int64(4)
+	if b.double.Load() {
+		period = 2
+	}
+
+	if b.count%period == 0 {
 		var wg sync.WaitGroup
 		wg.Add(2)
 		go func() {
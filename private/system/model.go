@@ -0,0 +1,37 @@
+package system
+
+// Model is a Game Boy hardware revision. It decides the boot ROM layout and the state the boot ROM leaves behind.
+type Model int
+
+const (
+	MODEL_DMG0 Model = iota // Early Japanese DMG
+	MODEL_DMG               // Original Game Boy
+	MODEL_MGB               // Game Boy Pocket
+	MODEL_SGB               // Super Game Boy
+	MODEL_CGB               // Game Boy Color
+)
+
+var modelNames = map[Model]string{
+	MODEL_DMG0: "DMG0",
+	MODEL_DMG:  "DMG",
+	MODEL_MGB:  "MGB",
+	MODEL_SGB:  "SGB",
+	MODEL_CGB:  "CGB",
+}
+
+func (m Model) String() string {
+	if name, ok := modelNames[m]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// ParseModel returns the model with the given name, e.g. "DMG" or "CGB".
+func ParseModel(name string) (Model, bool) {
+	for m, n := range modelNames {
+		if n == name {
+			return m, true
+		}
+	}
+	return 0, false
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"os"
 	"os/signal" // added import
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/colecrouter/gameboy-go/pkg/display/debug/reginfo"
 	"github.com/colecrouter/gameboy-go/pkg/system"
 	"github.com/colecrouter/gameboy-go/private/display"
+	"github.com/colecrouter/gameboy-go/private/display/debug/cheatlist"
+	"github.com/colecrouter/gameboy-go/private/display/debug/heatmap"
 	"github.com/colecrouter/gameboy-go/private/display/debug/logs"
 	"github.com/colecrouter/gameboy-go/private/display/debug/tilemap"
 	"github.com/colecrouter/gameboy-go/private/display/debug/tiles"
@@ -29,6 +32,12 @@ type Application struct {
 	openMenu    rune
 	refresh     *time.Ticker
 	lastOutput  string
+	rumbling    atomic.Bool
+}
+
+// keyHandler is implemented by menus that handle some keys themselves while they're open.
+type keyHandler interface {
+	HandleKey(key rune) bool
 }
 
 // NewApplication creates a new terminal application.
@@ -39,10 +48,17 @@ func NewApplication(gb *system.GameBoy) *Application {
 		'l': logs.NewLogMenu(),
 		'm': tilemap.NewTilemapDebug(gb.VRAM, &monochrome.Palette),
 		'r': reginfo.NewLogMenu(gb.IO),
+		'c': cheatlist.NewCheatMenu(gb.Cheats()),
+		'p': heatmap.NewHeatmapMenu(gb.Profiler()),
 	}
 	app.mainDisplay = lcd.NewDisplay(gb.PPU)
 	app.refresh = time.NewTicker(16 * time.Millisecond)
 
+	// Show rumble in the display title, since the terminal can't shake.
+	gb.OnRumble(func(on bool) {
+		app.rumbling.Store(on)
+	})
+
 	return app
 }
 
@@ -110,8 +126,9 @@ Loop:
 		case <-a.refresh.C:
 			a.render()
 
-			// Reset pressed buttons.
+			// Reset pressed buttons and tilt.
 			a.gb.Controller().ResetButtons()
+			a.gb.Tilt().SetTilt(0, 0)
 
 		case key := <-inputChan:
 			// Process menu bindings remain unchanged.
@@ -127,6 +144,13 @@ Loop:
 				}
 			}
 
+			// Some menus take their own keys while open, like number keys toggling cheats.
+			if h, ok := a.menus[a.openMenu].(keyHandler); ok && len(key) == 1 {
+				if h.HandleKey(rune(key[0])) {
+					continue
+				}
+			}
+
 			// Handle quit key.
 			if key == "q" {
 				// Stop the GameBoy runtime.
@@ -138,6 +162,23 @@ Loop:
 				break Loop
 			}
 
+			// Process tilt bindings.
+			tilt := a.gb.Tilt()
+			switch key {
+			case "t":
+				tilt.SetTilt(0, -1)
+				continue
+			case "g":
+				tilt.SetTilt(0, 1)
+				continue
+			case "f":
+				tilt.SetTilt(-1, 0)
+				continue
+			case "h":
+				tilt.SetTilt(1, 0)
+				continue
+			}
+
 			// Process controller bindings.
 			joy := a.gb.Controller()
 			var butt io.Button
@@ -165,6 +206,8 @@ Loop:
 
 			joy.SetButton(butt, true)
 		case <-sigChan:
+			// Stop the GameBoy runtime so saves are written.
+			a.gb.Stop()
 			break Loop
 		}
 	}
@@ -179,6 +222,11 @@ func (a *Application) render() {
 
 	clearScreen := "\033[H\033[2J"
 
+	a.mainDisplay.Config().Title = "Display"
+	if a.rumbling.Load() {
+		a.mainDisplay.Config().Title = "Display (Rumble)"
+	}
+
 	var screens [][]string
 	screens = append(screens, utils.DrawBox(a.mainDisplay, &utils.BoxOptions{Border: utils.BorderSingle}))
 	if a.openMenu != 0 && a.menus[a.openMenu] != nil {